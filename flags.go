@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"glancehud/internal/modules"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// parseHostFlags reads the --host-* overrides used to monitor the host from
+// inside a container, e.g.
+//
+//	glancehud --host-proc=/host/proc --host-sys=/host/sys --host-root=/host/root
+//
+// Unknown arguments are skipped rather than fatal, and parsing continues past
+// them: desktop launchers may add their own (macOS passes -psn_*).
+func parseHostFlags(args []string) modules.HostPaths {
+	var p modules.HostPaths
+	fs := flag.NewFlagSet("glancehud", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&p.Proc, "host-proc", "", "host /proc mount (overrides HOST_PROC and config host.proc)")
+	fs.StringVar(&p.Sys, "host-sys", "", "host /sys mount (overrides HOST_SYS and config host.sys)")
+	fs.StringVar(&p.Etc, "host-etc", "", "host /etc mount (overrides HOST_ETC and config host.etc)")
+	fs.StringVar(&p.Root, "host-root", "", "host / mount (overrides HOST_ROOT and config host.root)")
+	for len(args) > 0 {
+		err := fs.Parse(args)
+		if err != nil {
+			slog.Warn("Ignoring command-line argument", "error", err)
+		}
+		// Parse stops at a positional or malformed argument without
+		// consuming it; skip it and keep going.
+		rest := fs.Args()
+		if len(rest) > 0 && (err == nil || len(rest) == len(args)) {
+			rest = rest[1:]
+		}
+		args = rest
+	}
+	return p
+}
+
+// hostFlags parses the process's own command line.
+func hostFlags() modules.HostPaths {
+	return parseHostFlags(os.Args[1:])
+}
@@ -0,0 +1,12 @@
+package main
+
+import "testing"
+
+func TestParseHostFlags_SkipsUnknownArguments(t *testing.T) {
+	p := parseHostFlags([]string{
+		"-psn_0_12345", "--host-proc=/host/proc", "stray", "--host-sys", "/host/sys", "--bogus", "---x", "--host-root=/host/root",
+	})
+	if p.Proc != "/host/proc" || p.Sys != "/host/sys" || p.Root != "/host/root" {
+		t.Errorf("host flags after unknown arguments were lost: %+v", p)
+	}
+}
@@ -8,6 +8,7 @@ import {
   AppConfig,
   WidgetLayout,
   WidgetConfig,
+  AlertStatus,
 } from "./types"
 import { Events } from "@wailsio/runtime"
 import { HudGrid, calcGridWidth } from "./components/HudGrid"
@@ -67,6 +68,9 @@ function App() {
   const [modules, setModules] = useState<ModuleInfo[]>([])
   const [dataMap, setDataMap] = useState<Record<string, DataPayload>>({})
   const [historyMap, setHistoryMap] = useState<Record<string, number[]>>({})
+  const [seriesHistoryMap, setSeriesHistoryMap] = useState<
+    Record<string, Record<string, number[]>>
+  >({})
   const [isSettingsOpen, setIsSettingsOpen] = useState(false)
   const [isEditMode, setIsEditMode] = useState(false)
   const [isLocked, setIsLocked] = useState(false)
@@ -190,6 +194,19 @@ function App() {
             return { ...prev, [payload.id]: [...prevHistory, v].slice(-120) }
           })
         }
+
+        // Multi-series sparklines keep one buffer per series key
+        const series = payload.data?.series
+        if (series) {
+          setSeriesHistoryMap((prev) => {
+            const prevSeries = prev[payload.id] ?? {}
+            const next: Record<string, number[]> = {}
+            for (const [key, v] of Object.entries(series)) {
+              next[key] = [...(prevSeries[key] ?? []), v].slice(-120)
+            }
+            return { ...prev, [payload.id]: next }
+          })
+        }
       }
     })
 
@@ -234,6 +251,24 @@ function App() {
       }
     })
 
+    // Threshold alerts (rules in AppConfig.alerts)
+    const unsubAlertFire = Events.On("alert:fire", (event: any) => {
+      const a = (Array.isArray(event.data) ? event.data[0] : event.data) as AlertStatus
+      if (a?.name) {
+        debugLog(
+          a.severity === "critical" ? "ERR" : "WARN",
+          "Alert",
+          `${a.name} FIRING: ${a.widgetId} ${a.path} = ${a.value} ${a.op} ${a.threshold}`
+        )
+      }
+    })
+    const unsubAlertResolve = Events.On("alert:resolve", (event: any) => {
+      const a = (Array.isArray(event.data) ? event.data[0] : event.data) as AlertStatus
+      if (a?.name) {
+        debugLog("INFO", "Alert", `${a.name} resolved`)
+      }
+    })
+
     // Open settings from Tray
     const unsubOpenSettings = Events.On("open:settings", () => {
       setIsSettingsOpen(true)
@@ -252,6 +287,8 @@ function App() {
       unsubConfig()
       unsubMode()
       unsubOpenSettings()
+      unsubAlertFire()
+      unsubAlertResolve()
       unsubReload()
     }
   }, [loadConfig])
@@ -274,6 +311,7 @@ function App() {
           )
           isInitialLoadRef.current = false
           parsed.forEach((m) => knownModuleIdsRef.current.add(m.moduleId))
+          seedSparklineHistory(parsed)
         } else {
           // Detect newly registered sidecar widgets
           parsed.forEach((m) => {
@@ -296,6 +334,25 @@ function App() {
       })
   }
 
+  /** Re-seed sparkline buffers from the backend history store (survives reloads/restarts) */
+  const seedSparklineHistory = (mods: ModuleInfo[]) => {
+    mods
+      .filter((m) => m.enabled && m.config.type === "sparkline")
+      .forEach((m) => {
+        SystemService.GetHistory(m.config.id, 0, 0, 0)
+          .then((resp) => {
+            const seeded = (resp?.points ?? []).map((p) => p.v).slice(-120)
+            if (seeded.length === 0) return
+            debugLog("INFO", "History", `${m.config.id} seeded with ${seeded.length} points`)
+            // Backend history already includes any live points received so far
+            setHistoryMap((prev) => ({ ...prev, [m.config.id]: seeded }))
+          })
+          .catch(() => {
+            /* silent */
+          })
+      })
+  }
+
   const handleSettingsClose = () => {
     setIsSettingsOpen(false)
 
@@ -621,6 +678,7 @@ function App() {
                   modules={modules}
                   dataMap={dataMap}
                   historyMap={historyMap}
+                  seriesHistoryMap={seriesHistoryMap}
                   widgetLayouts={widgetLayouts}
                   gridColumns={RGL_MAX_COLS}
                   contentWidth={gridWidth}
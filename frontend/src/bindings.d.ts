@@ -16,6 +16,15 @@ declare module "*/bindings/glancehud/internal/service" {
       data: import("./types").DataPayload | null
     ): Promise<Record<string, any>>
     RemoveSidecar(id: string): Promise<void>
+    TriggerAction(moduleID: string, action: string): Promise<void>
+    GetHistory(
+      id: string,
+      from: number,
+      to: number,
+      step: number
+    ): Promise<import("./types").HistoryResponse>
+    GetAlerts(): Promise<{ alerts: import("./types").AlertStatus[]; snoozedUntil?: number }>
+    SnoozeNotifications(seconds: number): Promise<void>
   }
 }
 
@@ -5,6 +5,7 @@ interface Props {
   schema: ConfigSchema[]
   values: Record<string, any>
   onChange: (values: Record<string, any>) => void
+  onAction?: (action: string) => void
 }
 
 const inputStyle: React.CSSProperties = {
@@ -20,7 +21,7 @@ const inputStyle: React.CSSProperties = {
   boxSizing: "border-box",
 }
 
-export const DynamicForm: React.FC<Props> = ({ schema, values, onChange }) => {
+export const DynamicForm: React.FC<Props> = ({ schema, values, onChange, onAction }) => {
   const [formData, setFormData] = useState(values)
 
   useEffect(() => {
@@ -40,17 +41,19 @@ export const DynamicForm: React.FC<Props> = ({ schema, values, onChange }) => {
           key={field.name || field.label}
           style={{ display: "flex", flexDirection: "column", gap: 5 }}
         >
-          <label
-            style={{
-              fontSize: 11,
-              fontWeight: 500,
-              color: "var(--text-secondary)",
-              textTransform: "uppercase",
-              letterSpacing: "0.04em",
-            }}
-          >
-            {field.label}
-          </label>
+          {field.type !== "button" && (
+            <label
+              style={{
+                fontSize: 11,
+                fontWeight: 500,
+                color: "var(--text-secondary)",
+                textTransform: "uppercase",
+                letterSpacing: "0.04em",
+              }}
+            >
+              {field.label}
+            </label>
+          )}
 
           {field.type === "text" && (
             <input
@@ -139,6 +142,22 @@ export const DynamicForm: React.FC<Props> = ({ schema, values, onChange }) => {
             </div>
           )}
 
+          {field.type === "button" && (
+            <button
+              type="button"
+              onClick={() => field.action && onAction?.(field.action)}
+              style={{
+                ...inputStyle,
+                cursor: "pointer",
+                fontFamily: "inherit",
+                fontWeight: 500,
+                textAlign: "center",
+              }}
+            >
+              {field.label}
+            </button>
+          )}
+
           {field.type === "select" && (
             <select
               value={formData[field.name!] || ""}
@@ -138,6 +138,7 @@ interface HudGridProps {
   modules: ModuleInfo[]
   dataMap: Record<string, DataPayload>
   historyMap?: Record<string, number[]>
+  seriesHistoryMap?: Record<string, Record<string, number[]>>
   widgetLayouts: Record<string, WidgetLayout>
   gridColumns: number
   contentWidth: number // Actual visible width (derived from content extent)
@@ -166,6 +167,7 @@ export const HudGrid: React.FC<HudGridProps> = ({
   modules,
   dataMap,
   historyMap,
+  seriesHistoryMap,
   widgetLayouts,
   gridColumns,
   contentWidth,
@@ -256,6 +258,7 @@ export const HudGrid: React.FC<HudGridProps> = ({
                 config={mod.config}
                 data={dataMap[mod.config.id]}
                 history={historyMap?.[mod.config.id]}
+                seriesHistory={seriesHistoryMap?.[mod.config.id]}
               />
             </motion.div>
           </div>
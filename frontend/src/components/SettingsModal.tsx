@@ -83,6 +83,15 @@ export const SettingsModal: React.FC<Props> = ({ onClose, modules, currentConfig
     setConfig({ ...config, widgets: newWidgets })
   }
 
+  const handleAction = async (moduleId: string, action: string) => {
+    debugLog("INFO", "Settings", `action ${action} → ${moduleId}`)
+    try {
+      await SystemService.TriggerAction(moduleId, action)
+    } catch (err) {
+      debugLog("ERR", "Settings", `TriggerAction failed: ${err}`)
+    }
+  }
+
   const activeWidget = config?.widgets.find((w) => w.id === selectedModuleId)
   const activeSchema = selectedModuleId ? schemas[selectedModuleId] : []
   const activeModuleTitle = modules.find((m) => m.moduleId === selectedModuleId)?.config.title
@@ -302,6 +311,7 @@ export const SettingsModal: React.FC<Props> = ({ onClose, modules, currentConfig
             schema={activeSchema}
             values={activeWidget.props || {}}
             onChange={(vals) => handlePropsChange(activeWidget.id, vals)}
+            onAction={(action) => handleAction(activeWidget.id, action)}
           />
         ) : activeWidget && activeSchema?.length === 0 ? (
           <span
@@ -11,14 +11,23 @@ interface Props {
   config: RenderConfig
   data?: DataPayload
   history?: number[]
+  seriesHistory?: Record<string, number[]>
 }
 
-export const UniversalWidget: React.FC<Props> = ({ config, data, history: externalHistory }) => {
+export const UniversalWidget: React.FC<Props> = ({
+  config,
+  data,
+  history: externalHistory,
+  seriesHistory: externalSeriesHistory,
+}) => {
   const [containerRef, { width, height }] = useContainerSize()
 
   // Rolling history buffer — accumulated in App.tsx, trimmed here to maxPoints
   const maxPoints = (config.props?.maxPoints as number) || 30
   const history = (externalHistory ?? []).slice(-maxPoints)
+  const seriesHistory = Object.fromEntries(
+    Object.entries(externalSeriesHistory ?? {}).map(([k, v]) => [k, v.slice(-maxPoints)])
+  )
 
   const effectiveConfig = {
     ...config,
@@ -29,6 +38,8 @@ export const UniversalWidget: React.FC<Props> = ({ config, data, history: extern
   }
 
   const isOffline = effectiveConfig.props.isOffline === true
+  // Set by sources whose last run failed (e.g. exec widgets); data is stale
+  const error = typeof effectiveConfig.props.error === "string" ? effectiveConfig.props.error : ""
 
   const renderContent = () => {
     switch (config.type) {
@@ -74,6 +85,7 @@ export const UniversalWidget: React.FC<Props> = ({ config, data, history: extern
             config={effectiveConfig}
             data={data}
             history={history}
+            seriesHistory={seriesHistory}
             containerWidth={width}
             containerHeight={height}
           />
@@ -129,6 +141,30 @@ export const UniversalWidget: React.FC<Props> = ({ config, data, history: extern
           </span>
         </div>
       )}
+      {!isOffline && error && (
+        <div
+          title={error}
+          style={{
+            position: "absolute",
+            top: 2,
+            right: 2,
+            zIndex: 10,
+            maxWidth: "90%",
+            overflow: "hidden",
+            textOverflow: "ellipsis",
+            whiteSpace: "nowrap",
+            backgroundColor: "rgba(127,29,29,0.9)",
+            color: "#fecaca",
+            padding: "1px 6px",
+            borderRadius: 4,
+            fontSize: 10,
+            fontWeight: 600,
+            border: "1px solid #ef4444",
+          }}
+        >
+          ERROR · {error}
+        </div>
+      )}
     </div>
   )
 }
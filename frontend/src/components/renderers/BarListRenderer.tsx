@@ -21,7 +21,7 @@ export const BarListRenderer: React.FC<Props> = ({
   containerHeight,
 }) => {
   const items = Array.isArray(data?.items)
-    ? (data.items as Array<{ label: string; percent: number; value: string }>)
+    ? (data.items as Array<{ label: string; percent: number; value: string; color?: string }>)
     : []
   const fixedColor = config.props?.color as string | undefined
 
@@ -110,7 +110,7 @@ export const BarListRenderer: React.FC<Props> = ({
                 style={{
                   height: "100%",
                   borderRadius: barHeight / 2,
-                  background: fixedColor ?? statusColor(item.percent),
+                  background: item.color ?? fixedColor ?? statusColor(item.percent),
                 }}
               />
             </div>
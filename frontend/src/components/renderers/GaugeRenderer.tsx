@@ -23,11 +23,14 @@ export const GaugeRenderer: React.FC<Props> = ({
 }) => {
   const value = typeof data?.value === "number" ? data.value : 0
   const unit = (config.props?.unit as string) || "%"
+  // Ring fill and auto color use value as a share of props.max (default 100)
+  const max = (config.props?.max as number) || 100
+  const percent = (value / max) * 100
   const rawColor = config.props?.color as string | undefined
   // Only accept values that look like valid CSS colors (hex, rgb, hsl, named).
   // Reject Tailwind class names like "text-blue-500" that would silently break SVG stroke.
   const isValidCssColor = rawColor && /^(#|rgb|hsl|[a-z]+$)/i.test(rawColor)
-  const color = isValidCssColor ? rawColor : statusColorHex(value)
+  const color = isValidCssColor ? rawColor : statusColorHex(percent)
 
   // Scale factor based on container size relative to base
   const scaleW = containerWidth > 0 ? containerWidth / BASE_W : 1
@@ -54,7 +57,7 @@ export const GaugeRenderer: React.FC<Props> = ({
       }}
     >
       {/* Left: Ring */}
-      <RingProgress value={value} colour={color} size={ringSize} strokeWidth={strokeWidth} />
+      <RingProgress value={percent} colour={color} size={ringSize} strokeWidth={strokeWidth} />
 
       {/* Right: Text */}
       <div style={{ display: "flex", flexDirection: "column", minWidth: 0, flex: 1 }}>
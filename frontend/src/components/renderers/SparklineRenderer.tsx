@@ -1,5 +1,5 @@
 import React from "react"
-import { RenderConfig, DataPayload } from "../../types"
+import { RenderConfig, DataPayload, SparklineSeries } from "../../types"
 import { AnimatedNumber } from "../AnimatedNumber"
 import { statusColorHex } from "../../lib/statusColor"
 
@@ -11,6 +11,7 @@ interface Props {
   config: RenderConfig
   data?: DataPayload
   history: number[]
+  seriesHistory?: Record<string, number[]> // per-series buffers when props.series is set
   containerWidth: number
   containerHeight: number
 }
@@ -19,6 +20,7 @@ export const SparklineRenderer: React.FC<Props> = ({
   config,
   data,
   history,
+  seriesHistory,
   containerWidth,
   containerHeight,
 }) => {
@@ -42,30 +44,53 @@ export const SparklineRenderer: React.FC<Props> = ({
   const svgW = Math.max(1, containerWidth - padding * 2)
   const svgH = Math.max(1, containerHeight - headerHeight - padding * 1.5)
 
+  // Multi-series mode: one line per props.series entry, sharing the Y-axis
+  const seriesDefs = Array.isArray(config.props?.series)
+    ? (config.props.series as SparklineSeries[])
+    : []
+  const lines =
+    seriesDefs.length > 0
+      ? seriesDefs.map((s) => ({
+          key: s.key,
+          color: s.color || color,
+          values: seriesHistory?.[s.key] ?? [],
+        }))
+      : [{ key: "value", color, values: history }]
+  const allValues = lines.flatMap((l) => l.values)
+  const hasChart = lines.some((l) => l.values.length > 1)
+
   // Y-axis: auto-scale with 10% vertical padding so the line never touches edges
-  const minVal = history.length > 1 ? Math.min(...history) : 0
-  const maxVal = history.length > 1 ? Math.max(...history) : 100
+  const minVal = allValues.length > 1 ? Math.min(...allValues) : 0
+  const maxVal = allValues.length > 1 ? Math.max(...allValues) : 100
   const range = maxVal - minVal || 1
   const yInset = svgH * 0.1
 
-  const toSvgPoint = (v: number, i: number) => ({
-    x: history.length > 1 ? (i / (history.length - 1)) * svgW : svgW / 2,
-    y: svgH - yInset - ((v - minVal) / range) * (svgH - yInset * 2),
-  })
-
-  const points = history.map(toSvgPoint)
-  const linePoints = points.map((p) => `${p.x.toFixed(1)},${p.y.toFixed(1)}`).join(" ")
-  const fillPoints =
-    points.length > 0
-      ? [
-          ...points.map((p) => `${p.x.toFixed(1)},${p.y.toFixed(1)}`),
-          `${points[points.length - 1].x.toFixed(1)},${svgH}`,
-          `0,${svgH}`,
-        ].join(" ")
-      : ""
+  const toSvgPoints = (values: number[]) =>
+    values.map((v, i) => ({
+      x: values.length > 1 ? (i / (values.length - 1)) * svgW : svgW / 2,
+      y: svgH - yInset - ((v - minVal) / range) * (svgH - yInset * 2),
+    }))
 
   // Sanitize config.id for use as SVG gradient id (dots → dashes)
-  const gradId = `spark-grad-${config.id.replace(/\./g, "-")}`
+  const gradPrefix = `spark-grad-${config.id.replace(/\./g, "-")}`
+
+  const paths = lines
+    .filter((l) => l.values.length > 1)
+    .map((l) => {
+      const points = toSvgPoints(l.values)
+      const coords = points.map((p) => `${p.x.toFixed(1)},${p.y.toFixed(1)}`)
+      return {
+        ...l,
+        gradId: `${gradPrefix}-${l.key}`,
+        last: points[points.length - 1],
+        linePoints: coords.join(" "),
+        fillPoints: [
+          ...coords,
+          `${points[points.length - 1].x.toFixed(1)},${svgH}`,
+          `0,${svgH}`,
+        ].join(" "),
+      }
+    })
 
   return (
     <div
@@ -107,13 +132,19 @@ export const SparklineRenderer: React.FC<Props> = ({
             color: "var(--text-primary)",
           }}
         >
-          {value !== null ? <AnimatedNumber value={value} decimals={1} suffix={unit} /> : "--"}
+          {data?.displayValue ? (
+            data.displayValue
+          ) : value !== null ? (
+            <AnimatedNumber value={value} decimals={1} suffix={unit} />
+          ) : (
+            "--"
+          )}
         </span>
       </div>
 
       {/* Chart area */}
       <div style={{ flex: 1, overflow: "hidden" }}>
-        {history.length > 1 ? (
+        {hasChart ? (
           <svg
             width="100%"
             height="100%"
@@ -121,32 +152,33 @@ export const SparklineRenderer: React.FC<Props> = ({
             preserveAspectRatio="none"
           >
             <defs>
-              <linearGradient id={gradId} x1="0" y1="0" x2="0" y2="1">
-                <stop offset="0%" stopColor={color} stopOpacity="0.3" />
-                <stop offset="100%" stopColor={color} stopOpacity="0.02" />
-              </linearGradient>
+              {paths.map((p) => (
+                <linearGradient key={p.gradId} id={p.gradId} x1="0" y1="0" x2="0" y2="1">
+                  <stop offset="0%" stopColor={p.color} stopOpacity="0.3" />
+                  <stop offset="100%" stopColor={p.color} stopOpacity="0.02" />
+                </linearGradient>
+              ))}
             </defs>
 
-            {/* Gradient fill under line */}
-            <polygon points={fillPoints} fill={`url(#${gradId})`} />
-
-            {/* Trend line */}
-            <polyline
-              points={linePoints}
-              fill="none"
-              stroke={color}
-              strokeWidth={strokeWidth}
-              strokeLinecap="round"
-              strokeLinejoin="round"
-            />
-
-            {/* Latest value dot */}
-            <circle
-              cx={points[points.length - 1].x}
-              cy={points[points.length - 1].y}
-              r={dotRadius}
-              fill={color}
-            />
+            {paths.map((p) => (
+              <g key={p.key}>
+                {/* Gradient fill under line */}
+                <polygon points={p.fillPoints} fill={`url(#${p.gradId})`} />
+
+                {/* Trend line */}
+                <polyline
+                  points={p.linePoints}
+                  fill="none"
+                  stroke={p.color}
+                  strokeWidth={strokeWidth}
+                  strokeLinecap="round"
+                  strokeLinejoin="round"
+                />
+
+                {/* Latest value dot */}
+                <circle cx={p.last.x} cy={p.last.y} r={dotRadius} fill={p.color} />
+              </g>
+            ))}
           </svg>
         ) : (
           <div
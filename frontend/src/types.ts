@@ -13,12 +13,21 @@ export interface DataPayload {
   displayValue?: string
   items?: BarListItem[] | KeyValueItem[]
   props?: Record<string, any>
+  series?: Record<string, number> // multi-series sparkline: current value per series key
+}
+
+// Sparkline series definition (RenderConfig.props.series)
+export interface SparklineSeries {
+  key: string
+  label?: string
+  color?: string
 }
 
 export interface BarListItem {
   label: string
   percent: number
   value: string
+  color?: string // per-row bar color override
 }
 
 export interface KeyValueItem {
@@ -59,6 +68,133 @@ export interface AppConfig {
   opacity: number // 0.1~1.0, default 0.72
   windowMode: "normal" | "locked"
   debugConsole?: boolean
+  historySize?: number // samples kept per widget, default 3600
+  persistHistory?: boolean // save history to the config dir
+  alerts?: AlertRule[]
+  notifiers?: NotifierConfig[]
+  host?: HostPaths
+  exec?: ExecConfig[]
+  pulls?: PullConfig[]
+  statsd?: StatsDConfig
+  influx?: InfluxRule[]
+}
+
+/** Maps POST /api/write line-protocol points to a sidecar widget; first match wins. */
+export interface InfluxRule {
+  measurement: string // "*" matches any
+  tags?: Record<string, string> // tag values a point must carry
+  id?: string // widget ID template, default "influx.{measurement}"
+  value?: string // field used as DataPayload.value
+  rowTag?: string // tag whose values become rows / series
+  fields?: string[] // fields shown as rows, in order
+  template?: RenderConfig
+  schema?: ConfigSchema[]
+}
+
+/** Response of POST /api/write. */
+export interface WriteResponse {
+  status: "ok" | "partial" | "error"
+  written: number
+  props?: Record<string, Record<string, any>>
+  errors?: { line: number; error: string }[]
+}
+
+/** UDP StatsD listener on the API host; metrics become sparkline sidecars. */
+export interface StatsDConfig {
+  port?: number // default 8125
+  flush?: string // Go duration, default 5s
+  prefix?: string // widget ID prefix, default "statsd."
+  groupDepth?: number // >0 merges metrics sharing their first N name segments
+}
+
+/** A JSON or Prometheus URL GlanceHUD polls itself; behaves like a sidecar with module_id = id. */
+export interface PullConfig {
+  id: string
+  url: string
+  headers?: Record<string, string>
+  interval?: string // Go duration, default 10s
+  timeout?: string // Go duration, default 5s
+  template: RenderConfig
+  schema?: ConfigSchema[]
+  fields?: Record<string, string> // payload field → JSON path
+  prometheus?: PromScrape // scrape Prometheus text instead of JSON
+}
+
+/** Series selection for a pull source that scrapes a /metrics endpoint. */
+export interface PromScrape {
+  series: string // e.g. http_requests_total{job="api",code=~"5.."}
+  rate?: boolean // per-second rate between scrapes, for counters
+  groupBy?: string // label whose values become rows / series
+}
+
+/** A command whose output is shown as the widget "exec.<name>". */
+export interface ExecConfig {
+  name: string
+  title?: string
+  command: string[] // argv; ["sh", "-c", "..."] for pipelines
+  interval?: string // Go duration, default 10s
+  timeout?: string // Go duration, default 5s
+  type?: "sparkline" | "gauge" | "bar-list" | "key-value"
+  parser?: "number" | "regex" | "json" | "lines"
+  pattern?: string // regex: named groups value, label, displayValue, others → series
+  fields?: Record<string, string> // json: payload field → JSON path
+  props?: Record<string, any>
+}
+
+/** Host filesystem roots used when GlanceHUD runs inside a container. */
+export interface HostPaths {
+  proc?: string // e.g. "/host/proc"
+  sys?: string
+  etc?: string
+  root?: string // disk usage is read under this root
+}
+
+export interface NotifierConfig {
+  name: string
+  type: "desktop" | "webhook" | "command"
+  url?: string
+  method?: string
+  headers?: Record<string, string>
+  body?: string // Go text/template
+  command?: string[]
+  rateLimit?: string // Go duration
+  timeout?: string // Go duration
+}
+
+export interface AlertRule {
+  name: string
+  widgetId: string // short ID ("cpu") or render ID ("gpu.0")
+  path: string // "value", "items[0].percent", ...
+  op: ">" | ">=" | "<" | "<=" | "==" | "!="
+  threshold: number
+  for?: string // Go duration, e.g. "30s"
+  severity?: "info" | "warning" | "critical"
+  notify?: string[] // NotifierConfig names
+}
+
+export type AlertState = "ok" | "pending" | "firing"
+
+export interface AlertStatus {
+  name: string
+  widgetId: string // render ID
+  path: string
+  op: string
+  threshold: number
+  severity: string
+  state: AlertState
+  value?: number
+  since?: number // Unix milliseconds
+  notify?: string[]
+}
+
+export interface HistoryPoint {
+  t: number // Unix milliseconds
+  v: number
+}
+
+export interface HistoryResponse {
+  id: string
+  points: HistoryPoint[]
 }
 
 export type ConfigType = "text" | "number" | "bool" | "select" | "checkboxes" | "button"
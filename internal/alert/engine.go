@@ -0,0 +1,207 @@
+// Package alert evaluates threshold rules against widget data and tracks
+// their ok → pending → firing → ok lifecycle.
+package alert
+
+import (
+	"fmt"
+	"glancehud/internal/jsonpath"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"sync"
+	"time"
+)
+
+// DefaultSeverity is used when a rule does not specify one.
+const DefaultSeverity = "warning"
+
+// rule is a validated, compiled AlertRule.
+type rule struct {
+	modules.AlertRule
+	key  string
+	path jsonpath.Path
+	dur  time.Duration
+}
+
+type ruleState struct {
+	state protocol.AlertState
+	since time.Time
+	value *float64
+}
+
+// Engine holds compiled rules and their runtime state. It is safe for
+// concurrent use.
+type Engine struct {
+	mu     sync.Mutex
+	rules  []*rule
+	states map[string]*ruleState // keyed by rule.key
+}
+
+func NewEngine() *Engine {
+	return &Engine{states: make(map[string]*ruleState)}
+}
+
+// SetRules replaces the rule set. WidgetID must already be a render ID.
+// Invalid rules are skipped and reported in the returned errors. State is
+// preserved for rules that did not change, so saving unrelated settings does
+// not reset a pending or firing alert.
+func (e *Engine) SetRules(rules []modules.AlertRule) []error {
+	var errs []error
+	compiled := make([]*rule, 0, len(rules))
+	for i, r := range rules {
+		c, err := compile(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("alert rule %d (%q): %w", i, r.Name, err))
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	states := make(map[string]*ruleState, len(compiled))
+	for _, r := range compiled {
+		if st, ok := e.states[r.key]; ok {
+			states[r.key] = st
+		} else {
+			states[r.key] = &ruleState{state: protocol.AlertOK}
+		}
+	}
+	e.rules = compiled
+	e.states = states
+	return errs
+}
+
+// Evaluate checks every rule targeting widgetID against data and returns the
+// rules that transitioned to firing or back to ok.
+func (e *Engine) Evaluate(widgetID string, data *protocol.DataPayload, now time.Time) (fired, resolved []protocol.AlertStatus) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var doc any
+	normalised := false
+	for _, r := range e.rules {
+		if r.WidgetID != widgetID {
+			continue
+		}
+		if !normalised {
+			doc, _ = normalise(data)
+			normalised = true
+		}
+
+		st := e.states[r.key]
+		v, ok := lookupNumber(doc, r.path)
+		if ok {
+			st.value = &v
+		} else {
+			st.value = nil
+		}
+
+		switch {
+		case ok && compare(v, r.Op, r.Threshold):
+			if st.state == protocol.AlertOK {
+				st.state = protocol.AlertPending
+				st.since = now
+			}
+			if st.state == protocol.AlertPending && now.Sub(st.since) >= r.dur {
+				st.state = protocol.AlertFiring
+				st.since = now
+				fired = append(fired, status(r, st))
+			}
+		case st.state == protocol.AlertFiring:
+			st.state = protocol.AlertOK
+			st.since = now
+			resolved = append(resolved, status(r, st))
+		case st.state == protocol.AlertPending:
+			st.state = protocol.AlertOK
+			st.since = now
+		}
+	}
+	return fired, resolved
+}
+
+// Statuses returns the current state of every rule, in config order.
+func (e *Engine) Statuses() []protocol.AlertStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]protocol.AlertStatus, 0, len(e.rules))
+	for _, r := range e.rules {
+		out = append(out, status(r, e.states[r.key]))
+	}
+	return out
+}
+
+func compile(r modules.AlertRule) (*rule, error) {
+	if r.WidgetID == "" {
+		return nil, fmt.Errorf("widgetId required")
+	}
+	if !validOp(r.Op) {
+		return nil, fmt.Errorf("invalid op %q", r.Op)
+	}
+	if r.Path == "" {
+		return nil, fmt.Errorf("path required")
+	}
+	path, err := jsonpath.Parse(r.Path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	var dur time.Duration
+	if r.For != "" {
+		dur, err = time.ParseDuration(r.For)
+		if err != nil || dur < 0 {
+			return nil, fmt.Errorf("invalid for %q", r.For)
+		}
+	}
+	if r.Severity == "" {
+		r.Severity = DefaultSeverity
+	}
+	return &rule{
+		AlertRule: r,
+		key:       fmt.Sprintf("%s|%s|%s|%s|%g|%s", r.Name, r.WidgetID, r.Path, r.Op, r.Threshold, dur),
+		path:      path,
+		dur:       dur,
+	}, nil
+}
+
+func status(r *rule, st *ruleState) protocol.AlertStatus {
+	s := protocol.AlertStatus{
+		Name:      r.Name,
+		WidgetID:  r.WidgetID,
+		Path:      r.Path,
+		Op:        r.Op,
+		Threshold: r.Threshold,
+		Severity:  r.Severity,
+		State:     st.state,
+		Value:     st.value,
+		Notify:    r.Notify,
+	}
+	if !st.since.IsZero() {
+		s.Since = st.since.UnixMilli()
+	}
+	return s
+}
+
+func validOp(op string) bool {
+	switch op {
+	case ">", ">=", "<", "<=", "==", "!=":
+		return true
+	}
+	return false
+}
+
+func compare(v float64, op string, threshold float64) bool {
+	switch op {
+	case ">":
+		return v > threshold
+	case ">=":
+		return v >= threshold
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	case "==":
+		return v == threshold
+	case "!=":
+		return v != threshold
+	}
+	return false
+}
@@ -0,0 +1,173 @@
+package alert
+
+import (
+	"glancehud/internal/jsonpath"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"testing"
+	"time"
+)
+
+func cpuRule(forDur string) modules.AlertRule {
+	return modules.AlertRule{
+		Name:      "cpu-hot",
+		WidgetID:  "glancehud.core.cpu",
+		Path:      "value",
+		Op:        ">",
+		Threshold: 90,
+		For:       forDur,
+	}
+}
+
+// --- Lifecycle ---
+
+func TestEngine_FiresAfterForDuration(t *testing.T) {
+	e := NewEngine()
+	if errs := e.SetRules([]modules.AlertRule{cpuRule("30s")}); len(errs) != 0 {
+		t.Fatalf("SetRules: %v", errs)
+	}
+	t0 := time.Unix(1000, 0)
+	hot := &protocol.DataPayload{Value: 95.0}
+
+	fired, _ := e.Evaluate("glancehud.core.cpu", hot, t0)
+	if len(fired) != 0 {
+		t.Fatalf("want no fire on first breach, got %v", fired)
+	}
+	if st := e.Statuses()[0].State; st != protocol.AlertPending {
+		t.Errorf("want pending, got %s", st)
+	}
+
+	fired, _ = e.Evaluate("glancehud.core.cpu", hot, t0.Add(29*time.Second))
+	if len(fired) != 0 {
+		t.Fatalf("want no fire before 30s, got %v", fired)
+	}
+	fired, _ = e.Evaluate("glancehud.core.cpu", hot, t0.Add(30*time.Second))
+	if len(fired) != 1 || fired[0].State != protocol.AlertFiring {
+		t.Fatalf("want 1 firing alert, got %v", fired)
+	}
+	if fired[0].Value == nil || *fired[0].Value != 95 {
+		t.Errorf("want value 95, got %v", fired[0].Value)
+	}
+
+	// Still breaching: no duplicate fire
+	fired, _ = e.Evaluate("glancehud.core.cpu", hot, t0.Add(40*time.Second))
+	if len(fired) != 0 {
+		t.Errorf("want no re-fire, got %v", fired)
+	}
+
+	_, resolved := e.Evaluate("glancehud.core.cpu", &protocol.DataPayload{Value: 10.0}, t0.Add(50*time.Second))
+	if len(resolved) != 1 || resolved[0].State != protocol.AlertOK {
+		t.Fatalf("want 1 resolved alert, got %v", resolved)
+	}
+}
+
+func TestEngine_PendingClearsWithoutResolve(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]modules.AlertRule{cpuRule("1m")})
+	t0 := time.Unix(1000, 0)
+
+	e.Evaluate("glancehud.core.cpu", &protocol.DataPayload{Value: 95.0}, t0)
+	fired, resolved := e.Evaluate("glancehud.core.cpu", &protocol.DataPayload{Value: 50.0}, t0.Add(time.Second))
+	if len(fired) != 0 || len(resolved) != 0 {
+		t.Errorf("want no transitions, got fired=%v resolved=%v", fired, resolved)
+	}
+	if st := e.Statuses()[0].State; st != protocol.AlertOK {
+		t.Errorf("want ok, got %s", st)
+	}
+}
+
+func TestEngine_EmptyForFiresImmediately(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]modules.AlertRule{cpuRule("")})
+
+	fired, _ := e.Evaluate("glancehud.core.cpu", &protocol.DataPayload{Value: 91.0}, time.Now())
+	if len(fired) != 1 {
+		t.Fatalf("want immediate fire, got %v", fired)
+	}
+	if fired[0].Severity != DefaultSeverity {
+		t.Errorf("want severity %q, got %q", DefaultSeverity, fired[0].Severity)
+	}
+}
+
+func TestEngine_OtherWidgetIgnored(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]modules.AlertRule{cpuRule("")})
+
+	fired, _ := e.Evaluate("glancehud.core.mem", &protocol.DataPayload{Value: 99.0}, time.Now())
+	if len(fired) != 0 {
+		t.Errorf("want no fire for other widget, got %v", fired)
+	}
+}
+
+func TestEngine_SetRulesPreservesState(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]modules.AlertRule{cpuRule("")})
+	e.Evaluate("glancehud.core.cpu", &protocol.DataPayload{Value: 95.0}, time.Now())
+
+	// Re-applying the same rule keeps it firing
+	e.SetRules([]modules.AlertRule{cpuRule("")})
+	if st := e.Statuses()[0].State; st != protocol.AlertFiring {
+		t.Errorf("want firing after re-apply, got %s", st)
+	}
+
+	// Changing the threshold resets it
+	r := cpuRule("")
+	r.Threshold = 80
+	e.SetRules([]modules.AlertRule{r})
+	if st := e.Statuses()[0].State; st != protocol.AlertOK {
+		t.Errorf("want ok after rule change, got %s", st)
+	}
+}
+
+func TestEngine_InvalidRulesSkipped(t *testing.T) {
+	e := NewEngine()
+	bad := []modules.AlertRule{
+		{WidgetID: "x", Path: "value", Op: "~", Threshold: 1},
+		{WidgetID: "x", Path: "items[", Op: ">", Threshold: 1},
+		{WidgetID: "x", Path: "value", Op: ">", For: "soon"},
+		{Path: "value", Op: ">"},
+		cpuRule(""),
+	}
+	errs := e.SetRules(bad)
+	if len(errs) != 4 {
+		t.Errorf("want 4 errors, got %d: %v", len(errs), errs)
+	}
+	if n := len(e.Statuses()); n != 1 {
+		t.Errorf("want 1 valid rule, got %d", n)
+	}
+}
+
+// --- Paths ---
+
+func TestEngine_ItemPath(t *testing.T) {
+	e := NewEngine()
+	e.SetRules([]modules.AlertRule{{
+		Name:      "root-full",
+		WidgetID:  "glancehud.core.disk",
+		Path:      "items[1].percent",
+		Op:        ">=",
+		Threshold: 90,
+	}})
+	data := &protocol.DataPayload{Items: []protocol.BarListItem{
+		{Label: "/boot", Percent: 99},
+		{Label: "/", Percent: 92},
+	}}
+
+	fired, _ := e.Evaluate("glancehud.core.disk", data, time.Now())
+	if len(fired) != 1 || *fired[0].Value != 92 {
+		t.Errorf("want fire with value 92, got %v", fired)
+	}
+}
+
+func TestLookup_MissingPath(t *testing.T) {
+	doc, _ := normalise(&protocol.DataPayload{Value: "n/a"})
+	for _, path := range []string{"value", "items[0].percent", "props.max"} {
+		p, err := jsonpath.Parse(path)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", path, err)
+		}
+		if v, ok := lookupNumber(doc, p); ok {
+			t.Errorf("%s: want not found, got %v", path, v)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package alert
+
+import (
+	"encoding/json"
+	"glancehud/internal/jsonpath"
+)
+
+// lookupNumber resolves path against doc, a payload already normalised to
+// generic JSON. It returns false when the path does not exist or does not
+// end at a number.
+func lookupNumber(doc any, path jsonpath.Path) (float64, bool) {
+	v, _ := path.Lookup(doc)
+	f, ok := v.(float64)
+	return f, ok
+}
+
+// normalise converts a payload (typed structs or decoded JSON) into generic
+// JSON values so native modules and sidecars are addressed the same way.
+func normalise(payload any) (any, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
@@ -0,0 +1,33 @@
+package history
+
+import "glancehud/internal/protocol"
+
+// ring is a fixed-capacity circular buffer of samples.
+type ring struct {
+	buf   []protocol.HistoryPoint
+	start int // index of the oldest sample
+	n     int // number of valid samples
+}
+
+func newRing(capacity int) *ring {
+	return &ring{buf: make([]protocol.HistoryPoint, capacity)}
+}
+
+func (r *ring) push(p protocol.HistoryPoint) {
+	if r.n < len(r.buf) {
+		r.buf[(r.start+r.n)%len(r.buf)] = p
+		r.n++
+		return
+	}
+	r.buf[r.start] = p
+	r.start = (r.start + 1) % len(r.buf)
+}
+
+// slice returns a copy of the samples, oldest first.
+func (r *ring) slice() []protocol.HistoryPoint {
+	out := make([]protocol.HistoryPoint, r.n)
+	for i := 0; i < r.n; i++ {
+		out[i] = r.buf[(r.start+i)%len(r.buf)]
+	}
+	return out
+}
@@ -0,0 +1,159 @@
+package history
+
+import (
+	"encoding/json"
+	"glancehud/internal/protocol"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultCapacity is the number of samples kept per widget when no explicit
+// size is configured (one hour at a 1s update interval).
+const DefaultCapacity = 3600
+
+// Store is an in-memory time-series store holding a fixed-size ring of
+// samples per widget ID. It is safe for concurrent use.
+type Store struct {
+	mu       sync.RWMutex
+	capacity int
+	series   map[string]*ring
+}
+
+// NewStore creates a store that keeps at most capacity samples per widget.
+// A non-positive capacity falls back to DefaultCapacity.
+func NewStore(capacity int) *Store {
+	if capacity <= 0 {
+		capacity = DefaultCapacity
+	}
+	return &Store{
+		capacity: capacity,
+		series:   make(map[string]*ring),
+	}
+}
+
+// Record appends a sample for id, evicting the oldest one when full.
+func (s *Store) Record(id string, t time.Time, v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.series[id]
+	if !ok {
+		r = newRing(s.capacity)
+		s.series[id] = r
+	}
+	r.push(protocol.HistoryPoint{T: t.UnixMilli(), V: v})
+}
+
+// Query returns the samples for id within [from, to], oldest first. A zero
+// from or to leaves that side unbounded. When step > 0, samples are averaged
+// into step-aligned buckets and each bucket is stamped with its start time.
+func (s *Store) Query(id string, from, to time.Time, step time.Duration) []protocol.HistoryPoint {
+	s.mu.RLock()
+	r, ok := s.series[id]
+	var points []protocol.HistoryPoint
+	if ok {
+		points = r.slice()
+	}
+	s.mu.RUnlock()
+
+	filtered := make([]protocol.HistoryPoint, 0, len(points))
+	for _, p := range points {
+		if !from.IsZero() && p.T < from.UnixMilli() {
+			continue
+		}
+		if !to.IsZero() && p.T > to.UnixMilli() {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+
+	if step <= 0 {
+		return filtered
+	}
+	return downsample(filtered, step.Milliseconds())
+}
+
+// Remove drops all samples for id.
+func (s *Store) Remove(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.series, id)
+}
+
+// Save writes all series to path as JSON. The file is written to a temp file
+// first and renamed so a crash never leaves a truncated history behind.
+func (s *Store) Save(path string) error {
+	s.mu.RLock()
+	snapshot := make(map[string][]protocol.HistoryPoint, len(s.series))
+	for id, r := range s.series {
+		snapshot[id] = r.slice()
+	}
+	s.mu.RUnlock()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".history-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Load replaces the store contents with the series saved at path. Series
+// longer than the store capacity keep only their newest samples.
+func (s *Store) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var snapshot map[string][]protocol.HistoryPoint
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.series = make(map[string]*ring, len(snapshot))
+	for id, points := range snapshot {
+		r := newRing(s.capacity)
+		for _, p := range points {
+			r.push(p)
+		}
+		s.series[id] = r
+	}
+	return nil
+}
+
+// downsample averages points into buckets of stepMs milliseconds.
+func downsample(points []protocol.HistoryPoint, stepMs int64) []protocol.HistoryPoint {
+	var out []protocol.HistoryPoint
+	var bucket int64
+	var sum float64
+	var n int
+	for _, p := range points {
+		b := p.T - p.T%stepMs
+		if n > 0 && b != bucket {
+			out = append(out, protocol.HistoryPoint{T: bucket, V: sum / float64(n)})
+			sum, n = 0, 0
+		}
+		bucket = b
+		sum += p.V
+		n++
+	}
+	if n > 0 {
+		out = append(out, protocol.HistoryPoint{T: bucket, V: sum / float64(n)})
+	}
+	return out
+}
@@ -0,0 +1,96 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var t0 = time.UnixMilli(1_700_000_000_000)
+
+func TestStore_RingEvictsOldest(t *testing.T) {
+	s := NewStore(3)
+	for i := 0; i < 5; i++ {
+		s.Record("cpu", t0.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	got := s.Query("cpu", time.Time{}, time.Time{}, 0)
+	if len(got) != 3 {
+		t.Fatalf("expected 3 points, got %d", len(got))
+	}
+	for i, want := range []float64{2, 3, 4} {
+		if got[i].V != want {
+			t.Errorf("point %d: want %v, got %v", i, want, got[i].V)
+		}
+	}
+}
+
+func TestStore_QueryRange(t *testing.T) {
+	s := NewStore(10)
+	for i := 0; i < 5; i++ {
+		s.Record("cpu", t0.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	got := s.Query("cpu", t0.Add(time.Second), t0.Add(3*time.Second), 0)
+	if len(got) != 3 || got[0].V != 1 || got[2].V != 3 {
+		t.Errorf("unexpected range result: %+v", got)
+	}
+}
+
+func TestStore_QueryStepAverages(t *testing.T) {
+	s := NewStore(10)
+	// Four samples 1s apart, bucketed into 2s steps → (0+1)/2, (2+3)/2
+	for i := 0; i < 4; i++ {
+		s.Record("cpu", t0.Add(time.Duration(i)*time.Second), float64(i))
+	}
+
+	got := s.Query("cpu", time.Time{}, time.Time{}, 2*time.Second)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 buckets, got %+v", got)
+	}
+	if got[0].V != 0.5 || got[1].V != 2.5 {
+		t.Errorf("unexpected bucket averages: %+v", got)
+	}
+	if got[0].T%2000 != 0 {
+		t.Errorf("bucket not aligned to step: %d", got[0].T)
+	}
+}
+
+func TestStore_QueryUnknownID(t *testing.T) {
+	s := NewStore(10)
+	if got := s.Query("nope", time.Time{}, time.Time{}, 0); len(got) != 0 {
+		t.Errorf("expected empty result, got %+v", got)
+	}
+}
+
+func TestStore_SaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	s := NewStore(10)
+	s.Record("cpu", t0, 42)
+	s.Record("gpu.0", t0, 7)
+	if err := s.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	// Smaller capacity on load keeps only the newest samples
+	loaded := NewStore(1)
+	if err := loaded.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	got := loaded.Query("cpu", time.Time{}, time.Time{}, 0)
+	if len(got) != 1 || got[0].V != 42 || got[0].T != t0.UnixMilli() {
+		t.Errorf("unexpected cpu series after load: %+v", got)
+	}
+	if len(loaded.Query("gpu.0", time.Time{}, time.Time{}, 0)) != 1 {
+		t.Error("gpu.0 series missing after load")
+	}
+}
+
+func TestStore_Remove(t *testing.T) {
+	s := NewStore(10)
+	s.Record("cpu", t0, 1)
+	s.Remove("cpu")
+	if len(s.Query("cpu", time.Time{}, time.Time{}, 0)) != 0 {
+		t.Error("series should be gone after Remove")
+	}
+}
@@ -0,0 +1,191 @@
+// Package influx parses the InfluxDB line protocol:
+//
+//	measurement[,tag=value...] field=value[,field=value...] [timestamp]
+package influx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Point is one parsed line. Field values are float64 (floats, integers and
+// unsigned integers), string or bool.
+type Point struct {
+	Line        int // 1-based line number in the request body
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]any
+}
+
+// LineError reports a malformed or rejected line.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string { return fmt.Sprintf("line %d: %v", e.Line, e.Err) }
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// Parse reads every line of r. Blank lines and '#' comments are skipped.
+// Malformed lines do not stop parsing; each yields a *LineError instead.
+func Parse(r io.Reader) ([]Point, []error) {
+	var points []Point
+	var errs []error
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		p, err := ParseLine(line)
+		if err != nil {
+			errs = append(errs, &LineError{Line: n, Err: err})
+			continue
+		}
+		p.Line = n
+		points = append(points, p)
+	}
+	if err := sc.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	return points, errs
+}
+
+// ParseLine parses a single line. The timestamp, if any, must be an integer
+// and is otherwise ignored: widgets always show the latest write.
+func ParseLine(line string) (Point, error) {
+	sections := split(line, ' ')
+	if slices.Contains(sections, "") {
+		return Point{}, errors.New("unexpected whitespace")
+	}
+	if len(sections) < 2 {
+		return Point{}, errors.New("missing fields")
+	}
+	if len(sections) > 3 {
+		return Point{}, errors.New("unexpected text after timestamp")
+	}
+	if len(sections) == 3 {
+		if _, err := strconv.ParseInt(sections[2], 10, 64); err != nil {
+			return Point{}, fmt.Errorf("bad timestamp %q", sections[2])
+		}
+	}
+
+	key := split(sections[0], ',')
+	p := Point{
+		Measurement: unescape(key[0]),
+		Tags:        make(map[string]string, len(key)-1),
+		Fields:      make(map[string]any),
+	}
+	if p.Measurement == "" {
+		return Point{}, errors.New("missing measurement")
+	}
+	for _, kv := range key[1:] {
+		k, v, ok := cutUnescaped(kv, '=')
+		if !ok || k == "" || v == "" {
+			return Point{}, fmt.Errorf("bad tag %q", kv)
+		}
+		p.Tags[unescape(k)] = unescape(v)
+	}
+
+	for _, kv := range split(sections[1], ',') {
+		k, raw, ok := cutUnescaped(kv, '=')
+		if !ok || k == "" || raw == "" {
+			return Point{}, fmt.Errorf("bad field %q", kv)
+		}
+		v, err := parseFieldValue(raw)
+		if err != nil {
+			return Point{}, fmt.Errorf("field %s: %w", unescape(k), err)
+		}
+		p.Fields[unescape(k)] = v
+	}
+	return p, nil
+}
+
+func parseFieldValue(raw string) (any, error) {
+	if raw[0] == '"' {
+		if len(raw) < 2 || raw[len(raw)-1] != '"' {
+			return nil, errors.New("unterminated string")
+		}
+		return unescape(raw[1 : len(raw)-1]), nil
+	}
+	switch raw {
+	case "t", "T", "true", "True", "TRUE":
+		return true, nil
+	case "f", "F", "false", "False", "FALSE":
+		return false, nil
+	}
+	switch raw[len(raw)-1] {
+	case 'i':
+		n, err := strconv.ParseInt(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad integer %q", raw)
+		}
+		return float64(n), nil
+	case 'u':
+		n, err := strconv.ParseUint(raw[:len(raw)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad unsigned integer %q", raw)
+		}
+		return float64(n), nil
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("bad value %q", raw)
+	}
+	return f, nil
+}
+
+// split splits s at sep, skipping backslash-escaped characters and anything
+// inside double quotes.
+func split(s string, sep byte) []string {
+	var parts []string
+	start, quoted := 0, false
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// cutUnescaped is strings.Cut at the first unescaped sep.
+func cutUnescaped(s string, sep byte) (string, string, bool) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			return s[:i], s[i+1:], true
+		}
+	}
+	return s, "", false
+}
+
+// unescape removes the backslash from \, \= \space \" and \\.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(`,= "\`, s[i+1]) >= 0 {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
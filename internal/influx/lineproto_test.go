@@ -0,0 +1,57 @@
+package influx
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Point
+	}{
+		{
+			"cpu,host=web1,region=eu usage=42.5,cores=8i 1700000000000000000",
+			Point{Measurement: "cpu", Tags: map[string]string{"host": "web1", "region": "eu"},
+				Fields: map[string]any{"usage": 42.5, "cores": 8.0}},
+		},
+		{
+			`disk\ io,path=C:\\data\,x read=10u,ok=t,note="a \"b\", c=d"`,
+			Point{Measurement: "disk io", Tags: map[string]string{"path": `C:\data,x`},
+				Fields: map[string]any{"read": 10.0, "ok": true, "note": `a "b", c=d`}},
+		},
+		{
+			"queue depth=3",
+			Point{Measurement: "queue", Tags: map[string]string{}, Fields: map[string]any{"depth": 3.0}},
+		},
+	}
+	for _, tt := range tests {
+		got, err := ParseLine(tt.line)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n got %+v, %v\nwant %+v", tt.line, got, err, tt.want)
+		}
+	}
+
+	for _, bad := range []string{
+		"cpu", "cpu,host usage=1", "cpu usage", "cpu usage=abc", "cpu usage=1 notatime",
+		"cpu usage=1 1 extra", `cpu note="open`, "cpu usage=NaN", "cpu usage=1.5i", ",host=a usage=1",
+	} {
+		if _, err := ParseLine(bad); err == nil {
+			t.Errorf("want error for %q", bad)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	body := "# comment\ncpu usage=1\n\ncpu usage=\nmem used=2\n"
+	points, errs := Parse(strings.NewReader(body))
+	if len(points) != 2 || points[0].Line != 2 || points[1].Line != 5 {
+		t.Errorf("points: %+v", points)
+	}
+	var le *LineError
+	if len(errs) != 1 || !errors.As(errs[0], &le) || le.Line != 4 {
+		t.Errorf("errors: %v", errs)
+	}
+}
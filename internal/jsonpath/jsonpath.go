@@ -0,0 +1,71 @@
+// Package jsonpath resolves the dotted paths used by alert rules and JSON
+// mappings, e.g. "items[0].percent", against decoded JSON documents.
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// segment is one step of a path: an object key with an optional array
+// index, e.g. "items[2]". An empty key indexes the current value.
+type segment struct {
+	key   string
+	index int // -1 when the segment has no [i]
+}
+
+// Path is a compiled path. The zero Path refers to the whole document.
+type Path []segment
+
+// Parse parses paths like "status", "queues[0].depth" or "[1].n". An empty
+// path or "." refers to the whole document.
+func Parse(path string) (Path, error) {
+	path = strings.TrimPrefix(path, ".")
+	if path == "" {
+		return nil, nil
+	}
+	var p Path
+	for _, part := range strings.Split(path, ".") {
+		seg := segment{key: part, index: -1}
+		if open := strings.IndexByte(part, '['); open >= 0 {
+			if !strings.HasSuffix(part, "]") {
+				return nil, fmt.Errorf("unterminated index in %q", part)
+			}
+			idx, err := strconv.Atoi(part[open+1 : len(part)-1])
+			if err != nil || idx < 0 {
+				return nil, fmt.Errorf("invalid index in %q", part)
+			}
+			seg.key, seg.index = part[:open], idx
+		}
+		if seg.key == "" && seg.index < 0 {
+			return nil, fmt.Errorf("empty key in %q", path)
+		}
+		p = append(p, seg)
+	}
+	return p, nil
+}
+
+// Lookup resolves p against v, a decoded JSON value (map[string]any, []any,
+// float64, ...). It returns false when the path does not exist.
+func (p Path) Lookup(v any) (any, bool) {
+	for _, seg := range p {
+		if seg.key != "" {
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if v, ok = obj[seg.key]; !ok {
+				return nil, false
+			}
+		}
+		if seg.index >= 0 {
+			list, ok := v.([]any)
+			if !ok || seg.index >= len(list) {
+				return nil, false
+			}
+			v = list[seg.index]
+		}
+	}
+	return v, true
+}
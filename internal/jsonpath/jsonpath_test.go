@@ -0,0 +1,68 @@
+package jsonpath
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	var doc any
+	if err := json.Unmarshal([]byte(`{"value":3,"items":[{"percent":40},{"percent":92}],"rows":[[1,2]]}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]any{
+		"value":            3.0,
+		"items[1].percent": 92.0,
+		"rows[0][1]":       nil, // nested indexes are not supported
+		".value":           3.0,
+	}
+	for path, want := range cases {
+		p, err := Parse(path)
+		if want == nil {
+			if err == nil {
+				t.Errorf("Parse(%q): want error", path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", path, err)
+		}
+		if got, ok := p.Lookup(doc); !ok || got != want {
+			t.Errorf("%s: want %v, got %v (%v)", path, want, got, ok)
+		}
+	}
+
+	whole, _ := Parse(".")
+	if got, ok := whole.Lookup(doc); !ok || got == nil {
+		t.Error(`"." should resolve to the whole document`)
+	}
+	for _, path := range []string{"missing", "value.x", "items[5].percent", "value[0]"} {
+		p, err := Parse(path)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", path, err)
+		}
+		if v, ok := p.Lookup(doc); ok {
+			t.Errorf("%s: want not found, got %v", path, v)
+		}
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, path := range []string{"items[0", "items[-1]", "items[x]", "a..b"} {
+		if _, err := Parse(path); err == nil {
+			t.Errorf("Parse(%q): want error", path)
+		}
+	}
+}
+
+func TestLookup_IndexOnly(t *testing.T) {
+	var doc any
+	_ = json.Unmarshal([]byte(`[{"n":1},{"n":2}]`), &doc)
+	p, err := Parse("[1].n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := p.Lookup(doc); !ok || v != 2.0 {
+		t.Errorf("want 2, got %v (%v)", v, ok)
+	}
+}
@@ -0,0 +1,310 @@
+package modules
+
+import (
+	"bufio"
+	"fmt"
+	"glancehud/internal/protocol"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// CgroupModule reports CPU, memory and I/O of selected cgroup v2 groups
+// (systemd slices, container scopes) against their own limits.
+type CgroupModule struct {
+	root  string   // cgroup2 mount; empty means $HOST_SYS/fs/cgroup
+	paths []string // relative to root; empty means our own cgroup
+
+	prev map[string]cgroupSample
+}
+
+// cgroupSample is the last seen cumulative counters of one cgroup.
+type cgroupSample struct {
+	usageUsec uint64
+	rbytes    uint64
+	wbytes    uint64
+	at        time.Time
+}
+
+// cgroupStat is one cgroup's usage over the last interval.
+type cgroupStat struct {
+	path              string
+	cores             float64 // CPU cores in use
+	coreLimit         float64 // cpu.max quota/period; 0 means unlimited
+	memCurrent        uint64
+	memMax            uint64 // 0 means unlimited
+	readBps, writeBps float64
+	hasIO             bool
+}
+
+func NewCgroupModule() *CgroupModule {
+	return &CgroupModule{
+		prev: make(map[string]cgroupSample),
+	}
+}
+
+func (m *CgroupModule) ID() string {
+	return "cgroup"
+}
+
+func (m *CgroupModule) Interval() time.Duration {
+	return 2 * time.Second
+}
+
+func (m *CgroupModule) ApplyConfig(props map[string]interface{}) {
+	if val, ok := props["root"].(string); ok {
+		m.root = strings.TrimSpace(val)
+	}
+	if val, ok := props["paths"].(string); ok {
+		m.paths = nil
+		for _, p := range strings.Split(val, ",") {
+			if p = strings.Trim(strings.TrimSpace(p), "/"); p != "" {
+				m.paths = append(m.paths, p)
+			}
+		}
+	}
+}
+
+func (m *CgroupModule) GetConfigSchema() []protocol.ConfigSchema {
+	return []protocol.ConfigSchema{
+		{
+			Name:  "paths",
+			Label: "Cgroups (comma-separated, e.g. user.slice/user-1000.slice)",
+			Type:  protocol.ConfigText,
+		},
+		{
+			Name:  "root",
+			Label: "Cgroup Root (default /sys/fs/cgroup)",
+			Type:  protocol.ConfigText,
+		},
+	}
+}
+
+func (m *CgroupModule) GetRenderConfig() protocol.RenderConfig {
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.cgroup",
+		Type:  protocol.TypeBarList,
+		Title: "Cgroups",
+	}
+}
+
+func (m *CgroupModule) Update() (*protocol.DataPayload, error) {
+	root := m.rootDir()
+	paths := m.paths
+	if len(paths) == 0 {
+		self, err := ownCgroup()
+		if err != nil {
+			return nil, err
+		}
+		paths = []string{self}
+	}
+
+	stats := m.collect(root, paths, time.Now())
+
+	var hostMem uint64
+	if vm, err := mem.VirtualMemoryWithContext(hostContext()); err == nil {
+		hostMem = vm.Total
+	}
+	// Online cores read through the host proc/sys mounts, unlike
+	// runtime.NumCPU, which is this process's own affinity set.
+	hostCores, _ := cpu.CountsWithContext(hostContext(), true)
+
+	items := make([]protocol.BarListItem, 0, len(stats)*3)
+	for _, st := range stats {
+		items = append(items, cgroupItems(st, hostCores, hostMem)...)
+	}
+	if len(items) == 0 {
+		items = []protocol.BarListItem{{Label: "No cgroups found", Value: "—"}}
+	}
+	return &protocol.DataPayload{Items: items}, nil
+}
+
+func (m *CgroupModule) rootDir() string {
+	if m.root != "" {
+		return m.root
+	}
+	return hostSys("fs", "cgroup")
+}
+
+// collect reads each cgroup and derives rates from the previous sample. As
+// with the other rate modules, a cgroup seen for the first time reports zero
+// CPU and I/O rather than its lifetime totals. Missing cgroups (a container
+// that stopped) are skipped.
+func (m *CgroupModule) collect(root string, paths []string, now time.Time) []cgroupStat {
+	next := make(map[string]cgroupSample, len(paths))
+	stats := make([]cgroupStat, 0, len(paths))
+	for _, p := range paths {
+		dir := filepath.Join(root, p)
+		usage, err := readFlatKey(filepath.Join(dir, "cpu.stat"), "usage_usec")
+		if err != nil {
+			continue
+		}
+		st := cgroupStat{path: p, coreLimit: readCPUMax(filepath.Join(dir, "cpu.max"))}
+		st.memCurrent, _ = readUintFile(filepath.Join(dir, "memory.current"))
+		st.memMax, _ = readUintFile(filepath.Join(dir, "memory.max")) // "max" fails to parse → 0
+		rbytes, wbytes, ioErr := readIOStat(filepath.Join(dir, "io.stat"))
+		st.hasIO = ioErr == nil
+
+		cur := cgroupSample{usageUsec: usage, rbytes: rbytes, wbytes: wbytes, at: now}
+		next[p] = cur
+		if prev, ok := m.prev[p]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+			if elapsed > 0 && cur.usageUsec >= prev.usageUsec {
+				st.cores = float64(cur.usageUsec-prev.usageUsec) / 1e6 / elapsed
+			}
+			if elapsed > 0 && cur.rbytes >= prev.rbytes && cur.wbytes >= prev.wbytes {
+				st.readBps = float64(cur.rbytes-prev.rbytes) / elapsed
+				st.writeBps = float64(cur.wbytes-prev.wbytes) / elapsed
+			}
+		}
+		stats = append(stats, st)
+	}
+	m.prev = next
+	return stats
+}
+
+// cgroupItems renders one cgroup as CPU, memory and (when io.stat exists)
+// I/O rows. Usage is shown against the cgroup's own limit, falling back to
+// host capacity when unlimited. hostCores of 0 means unknown, in which case
+// the cores available to this process are used instead.
+func cgroupItems(st cgroupStat, hostCores int, hostMem uint64) []protocol.BarListItem {
+	name := st.path
+	if name == "" {
+		name = "/"
+	}
+
+	coreCap, capLabel := st.coreLimit, fmt.Sprintf("%.4g", st.coreLimit)
+	if coreCap == 0 {
+		coreCap, capLabel = float64(hostCores), "host"
+		if hostCores <= 0 {
+			coreCap, capLabel = float64(runtime.NumCPU()), "available"
+		}
+	}
+	memCap, memLabel := st.memMax, ""
+	if memCap == 0 {
+		memCap, memLabel = hostMem, "host"
+	} else {
+		memLabel = formatBytes(memCap)
+	}
+	var memPct float64
+	if memCap > 0 {
+		memPct = float64(st.memCurrent) / float64(memCap) * 100
+	}
+
+	items := []protocol.BarListItem{
+		{
+			Label:   name + " cpu",
+			Percent: round(min(100, st.cores/coreCap*100), 1),
+			Value:   fmt.Sprintf("%.2f / %s cores", st.cores, capLabel),
+		},
+		{
+			Label:   name + " mem",
+			Percent: round(min(100, memPct), 1),
+			Value:   fmt.Sprintf("%s / %s", formatBytes(st.memCurrent), memLabel),
+		},
+	}
+	if st.hasIO {
+		items = append(items, protocol.BarListItem{
+			Label: name + " io",
+			Value: fmt.Sprintf("R %s W %s", formatRate(st.readBps, false), formatRate(st.writeBps, false)),
+		})
+	}
+	return items
+}
+
+// ownCgroup returns this process's cgroup v2 path from /proc/self/cgroup
+// ("0::/user.slice/user-1000.slice/session-2.scope").
+func ownCgroup() (string, error) {
+	data, err := os.ReadFile(hostProc("self", "cgroup"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := strings.CutPrefix(line, "0::"); ok {
+			return strings.Trim(rest, "/"), nil
+		}
+	}
+	return "", fmt.Errorf("no cgroup v2 entry in /proc/self/cgroup")
+}
+
+// readFlatKey reads one key from a flat-keyed file such as cpu.stat
+// ("usage_usec 123\nuser_usec 45\n...").
+func readFlatKey(path, key string) (uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		k, v, ok := strings.Cut(sc.Text(), " ")
+		if ok && k == key {
+			return strconv.ParseUint(strings.TrimSpace(v), 10, 64)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("%s: no %s", path, key)
+}
+
+func readUintFile(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// readCPUMax parses cpu.max ("200000 100000" → 2 cores, "max 100000" → 0).
+func readCPUMax(path string) float64 {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return 0
+	}
+	quota, err1 := strconv.ParseFloat(fields[0], 64)
+	period, err2 := strconv.ParseFloat(fields[1], 64)
+	if err1 != nil || err2 != nil || period <= 0 {
+		return 0
+	}
+	return quota / period
+}
+
+// readIOStat sums rbytes and wbytes across devices in io.stat
+// ("8:0 rbytes=1 wbytes=2 rios=3 wios=4 dbytes=0 dios=0").
+func readIOStat(path string) (rbytes, wbytes uint64, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		for _, f := range fields[min(1, len(fields)):] {
+			k, v, ok := strings.Cut(f, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch k {
+			case "rbytes":
+				rbytes += n
+			case "wbytes":
+				wbytes += n
+			}
+		}
+	}
+	return rbytes, wbytes, nil
+}
@@ -0,0 +1,110 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeCgroup builds a fake cgroup v2 directory under root.
+func writeCgroup(t *testing.T, root, path string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCgroupModule_Collect(t *testing.T) {
+	root := t.TempDir()
+	writeCgroup(t, root, "system.slice/app.scope", map[string]string{
+		"cpu.stat":       "usage_usec 1000000\nuser_usec 600000\nsystem_usec 400000\n",
+		"cpu.max":        "200000 100000\n",
+		"memory.current": "536870912\n",
+		"memory.max":     "1073741824\n",
+		"io.stat":        "8:0 rbytes=1000 wbytes=2000 rios=1 wios=2 dbytes=0 dios=0\n",
+	})
+
+	m := NewCgroupModule()
+	m.ApplyConfig(map[string]interface{}{"root": root, "paths": "/system.slice/app.scope/, missing.slice"})
+	t0 := time.Unix(1000, 0)
+
+	first := m.collect(root, m.paths, t0)
+	if len(first) != 1 {
+		t.Fatalf("missing cgroup should be skipped, got %+v", first)
+	}
+	if first[0].cores != 0 || first[0].readBps != 0 {
+		t.Errorf("first sample should report 0, got %+v", first[0])
+	}
+
+	writeCgroup(t, root, "system.slice/app.scope", map[string]string{
+		"cpu.stat": "usage_usec 3000000\n",
+		"io.stat":  "8:0 rbytes=3000 wbytes=2000\n8:16 rbytes=0 wbytes=4000\n",
+	})
+	got := m.collect(root, m.paths, t0.Add(2*time.Second))[0]
+	if got.cores != 1 || got.coreLimit != 2 {
+		t.Errorf("want 1 of 2 cores, got %v of %v", got.cores, got.coreLimit)
+	}
+	if got.memCurrent != 512<<20 || got.memMax != 1<<30 {
+		t.Errorf("unexpected memory %+v", got)
+	}
+	if got.readBps != 1000 || got.writeBps != 2000 {
+		t.Errorf("want read=1000 write=2000 B/s, got %v %v", got.readBps, got.writeBps)
+	}
+
+	items := cgroupItems(got, 8, 0)
+	if len(items) != 3 {
+		t.Fatalf("want cpu, mem and io rows, got %+v", items)
+	}
+	if items[0].Percent != 50 || items[0].Value != "1.00 / 2 cores" {
+		t.Errorf("unexpected cpu row %+v", items[0])
+	}
+	if items[1].Percent != 50 || items[1].Value != "512.0 MB / 1.0 GB" {
+		t.Errorf("unexpected mem row %+v", items[1])
+	}
+}
+
+func TestCgroupModule_Unlimited(t *testing.T) {
+	root := t.TempDir()
+	writeCgroup(t, root, "user.slice", map[string]string{
+		"cpu.stat":       "usage_usec 0\n",
+		"cpu.max":        "max 100000\n",
+		"memory.current": "1024\n",
+		"memory.max":     "max\n",
+	})
+
+	m := NewCgroupModule()
+	got := m.collect(root, []string{"user.slice"}, time.Unix(1000, 0))[0]
+	if got.coreLimit != 0 || got.memMax != 0 || got.hasIO {
+		t.Errorf("want unlimited without io, got %+v", got)
+	}
+	items := cgroupItems(got, 8, 4096)
+	if len(items) != 2 || items[1].Percent != 25 || items[1].Value != "1.0 KB / host" {
+		t.Errorf("unlimited memory should use host total, got %+v", items)
+	}
+	if items[0].Value != "0.00 / host cores" {
+		t.Errorf("unlimited cpu should use host cores, got %+v", items[0])
+	}
+	if items := cgroupItems(got, 0, 4096); items[0].Value != "0.00 / available cores" {
+		t.Errorf("unknown host cores should fall back to available, got %+v", items[0])
+	}
+}
+
+func TestOwnCgroup(t *testing.T) {
+	root := t.TempDir()
+	writeCgroup(t, root, "self", map[string]string{
+		"cgroup": "0::/user.slice/user-1000.slice/session-2.scope\n",
+	})
+	t.Setenv("HOST_PROC", root)
+
+	got, err := ownCgroup()
+	if err != nil || got != "user.slice/user-1000.slice/session-2.scope" {
+		t.Errorf("unexpected cgroup %q, %v", got, err)
+	}
+}
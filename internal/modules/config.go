@@ -2,6 +2,7 @@ package modules
 
 import (
 	"encoding/json"
+	"glancehud/internal/protocol"
 	"os"
 	"path/filepath"
 	"sync"
@@ -29,6 +30,107 @@ type AppConfig struct {
 	Opacity      float64        `json:"opacity"`      // 0.1~1.0, default 0.72
 	WindowMode   string         `json:"windowMode"`   // "normal"|"locked"
 	DebugConsole bool           `json:"debugConsole"` // show debug console
+
+	HistorySize    int  `json:"historySize,omitempty"`    // samples kept per widget, default 3600
+	PersistHistory bool `json:"persistHistory,omitempty"` // save history to the config dir
+
+	Alerts    []AlertRule      `json:"alerts,omitempty"`    // threshold rules evaluated on every update
+	Notifiers []NotifierConfig `json:"notifiers,omitempty"` // channels alerts can notify via AlertRule.Notify
+
+	Host HostPaths `json:"host,omitzero"` // host /proc, /sys, /etc and / when running in a container
+
+	Exec  []ExecConfig `json:"exec,omitempty"`  // shell commands shown as widgets, one widget each
+	Pulls []PullConfig `json:"pulls,omitempty"` // JSON URLs polled as sidecar-style widgets
+
+	StatsD *StatsDConfig `json:"statsd,omitempty"` // UDP StatsD listener; nil leaves it off
+	Influx []InfluxRule  `json:"influx,omitempty"` // maps POST /api/write line protocol to widgets; first match wins
+}
+
+// AlertRule fires when the value at Path in a widget's DataPayload satisfies
+// Op Threshold continuously for the For duration.
+type AlertRule struct {
+	Name      string   `json:"name"`
+	WidgetID  string   `json:"widgetId"` // short ID ("cpu") or render ID ("gpu.0")
+	Path      string   `json:"path"`     // "value", "items[0].percent", ...
+	Op        string   `json:"op"`       // ">", ">=", "<", "<=", "==", "!="
+	Threshold float64  `json:"threshold"`
+	For       string   `json:"for,omitempty"`      // Go duration, e.g. "30s"; empty fires immediately
+	Severity  string   `json:"severity,omitempty"` // "info"|"warning"|"critical", default "warning"
+	Notify    []string `json:"notify,omitempty"`   // NotifierConfig names to send fire/resolve to
+}
+
+// NotifierConfig describes a notification channel. Body and Command entries
+// are Go text/templates rendered with the alert event.
+type NotifierConfig struct {
+	Name      string            `json:"name"`
+	Type      string            `json:"type"`                // "desktop"|"webhook"|"command"
+	URL       string            `json:"url,omitempty"`       // webhook
+	Method    string            `json:"method,omitempty"`    // webhook, default POST
+	Headers   map[string]string `json:"headers,omitempty"`   // webhook
+	Body      string            `json:"body,omitempty"`      // webhook; empty sends the event as JSON
+	Command   []string          `json:"command,omitempty"`   // command argv
+	RateLimit string            `json:"rateLimit,omitempty"` // Go duration, minimum gap between sends
+	Timeout   string            `json:"timeout,omitempty"`   // Go duration, default 10s
+}
+
+// ExecConfig describes a command whose output becomes a widget with ID
+// "exec.<Name>". Parser selects how stdout is turned into a DataPayload.
+type ExecConfig struct {
+	Name     string            `json:"name"`
+	Title    string            `json:"title,omitempty"`    // default Name
+	Command  []string          `json:"command"`            // argv; use ["sh", "-c", "..."] for pipelines
+	Interval string            `json:"interval,omitempty"` // Go duration, default 10s
+	Timeout  string            `json:"timeout,omitempty"`  // Go duration, default 5s
+	Type     string            `json:"type,omitempty"`     // protocol.ComponentType, default "sparkline"
+	Parser   string            `json:"parser,omitempty"`   // "number"|"regex"|"json"|"lines", default "number"
+	Pattern  string            `json:"pattern,omitempty"`  // regex: named groups map to payload fields
+	Fields   map[string]string `json:"fields,omitempty"`   // json: payload field → JSON path
+	Props    map[string]any    `json:"props,omitempty"`    // static render props (unit, max, color...)
+}
+
+// PullConfig describes a JSON URL GlanceHUD polls itself. The widget behaves
+// like a sidecar with module_id ID: same template, schema and offline overlay.
+type PullConfig struct {
+	ID       string                  `json:"id"`
+	URL      string                  `json:"url"`
+	Headers  map[string]string       `json:"headers,omitempty"`
+	Interval string                  `json:"interval,omitempty"` // Go duration, default 10s
+	Timeout  string                  `json:"timeout,omitempty"`  // Go duration, default 5s
+	Template protocol.RenderConfig   `json:"template"`           // as SidecarRequest.Template; id is set to ID
+	Schema   []protocol.ConfigSchema `json:"schema,omitempty"`
+	Fields   map[string]string       `json:"fields,omitempty"` // payload field → JSON path; empty means the body is a DataPayload
+
+	Prometheus *PromScrape `json:"prometheus,omitempty"` // scrape a Prometheus text endpoint instead of JSON
+}
+
+// PromScrape selects series from a Prometheus/OpenMetrics text endpoint.
+type PromScrape struct {
+	Series  string `json:"series"`            // selector, e.g. http_requests_total{job="api",code=~"5.."}
+	Rate    bool   `json:"rate,omitempty"`    // per-second rate between scrapes, for counters
+	GroupBy string `json:"groupBy,omitempty"` // label whose values become rows / series; empty sums all matches
+}
+
+// StatsDConfig enables a StatsD UDP listener on the API host. Each metric, or
+// each group of metrics sharing a prefix, becomes a sparkline sidecar widget.
+// It is read once at startup; changes take effect after a restart.
+type StatsDConfig struct {
+	Port       int    `json:"port,omitempty"`       // default 8125
+	Flush      string `json:"flush,omitempty"`      // Go duration, default 5s; must stay below the sidecar TTL
+	Prefix     string `json:"prefix,omitempty"`     // widget ID prefix, default "statsd."
+	GroupDepth int    `json:"groupDepth,omitempty"` // >0 merges metrics sharing their first N name segments into one widget
+}
+
+// InfluxRule maps line-protocol points to a sidecar widget. ID may reference
+// "{measurement}" and any tag as "{<tag>}", e.g. "influx.{measurement}.{host}".
+type InfluxRule struct {
+	Measurement string                  `json:"measurement"`       // measurement to match; "*" matches any
+	Tags        map[string]string       `json:"tags,omitempty"`    // tag values a point must carry
+	ID          string                  `json:"id,omitempty"`      // widget ID template, default "influx.{measurement}"
+	Value       string                  `json:"value,omitempty"`   // field for DataPayload.Value; default "value", or the only numeric field
+	RowTag      string                  `json:"rowTag,omitempty"`  // tag whose values become rows / series, one point each
+	Fields      []string                `json:"fields,omitempty"`  // fields shown as rows, in order; default all, sorted
+	Template    protocol.RenderConfig   `json:"template,omitzero"` // default: sparkline titled with the widget ID
+	Schema      []protocol.ConfigSchema `json:"schema,omitempty"`
 }
 
 type ConfigService struct {
@@ -56,15 +158,30 @@ func NewConfigService(configDir string, modules map[string]Module) (*ConfigServi
 	if err := cs.Load(); err != nil {
 		// If load fails (e.g. file not found), save default
 		_ = cs.Save()
+	} else {
+		// Modules added in newer versions appear (disabled) in Settings
+		cs.Config.Widgets = appendMissingWidgets(cs.Config.Widgets, defaults)
 	}
 
 	return cs, nil
 }
 
+// optionalModules are listed in a fresh config but start disabled; users turn
+// them on from Settings.
+var optionalModules = map[string]bool{
+	"proc":     true,
+	"diskio":   true,
+	"sensors":  true,
+	"cgroup":   true,
+	"watchdog": true,
+	"ports":    true,
+	"health":   true,
+}
+
 // buildDefaultWidgets derives default WidgetConfig from each module's ConfigSchema.
 func buildDefaultWidgets(modules map[string]Module) []WidgetConfig {
 	// Fixed order so config.json is deterministic
-	order := []string{"cpu", "mem", "disk", "net"}
+	order := []string{"cpu", "mem", "disk", "net", "proc", "diskio", "sensors", "cgroup", "watchdog", "ports", "health"}
 
 	var widgets []WidgetConfig
 	for _, id := range order {
@@ -82,7 +199,7 @@ func buildDefaultWidgets(modules map[string]Module) []WidgetConfig {
 
 		wc := WidgetConfig{
 			ID:      id,
-			Enabled: true,
+			Enabled: !optionalModules[id],
 		}
 		if len(props) > 0 {
 			wc.Props = props
@@ -92,6 +209,22 @@ func buildDefaultWidgets(modules map[string]Module) []WidgetConfig {
 	return widgets
 }
 
+// appendMissingWidgets adds disabled entries for native modules that an
+// existing config.json predates, so they can be enabled from Settings.
+func appendMissingWidgets(widgets, defaults []WidgetConfig) []WidgetConfig {
+	present := make(map[string]bool, len(widgets))
+	for _, w := range widgets {
+		present[w.ID] = true
+	}
+	for _, d := range defaults {
+		if !present[d.ID] {
+			d.Enabled = false
+			widgets = append(widgets, d)
+		}
+	}
+	return widgets
+}
+
 func (cs *ConfigService) Load() error {
 	cs.mu.Lock()
 	defer cs.mu.Unlock()
@@ -133,6 +266,9 @@ func (cs *ConfigService) withDefaults(cfg AppConfig) AppConfig {
 	if cfg.WindowMode == "" {
 		cfg.WindowMode = "normal"
 	}
+	if cfg.HistorySize <= 0 {
+		cfg.HistorySize = 3600 // 1h at 1s interval
+	}
 	return cfg
 }
 
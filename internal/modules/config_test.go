@@ -128,6 +128,46 @@ func TestBuildDefaultWidgets_SkipsMissingModules(t *testing.T) {
 	}
 }
 
+func TestBuildDefaultWidgets_OptionalModulesDisabled(t *testing.T) {
+	mods := map[string]Module{
+		"cpu":  &stubModule{id: "cpu"},
+		"proc": &stubModule{id: "proc"},
+	}
+	widgets := buildDefaultWidgets(mods)
+	if len(widgets) != 2 {
+		t.Fatalf("expected 2 widgets, got %d", len(widgets))
+	}
+	if !widgets[0].Enabled {
+		t.Error("cpu should be enabled by default")
+	}
+	if widgets[1].ID != "proc" || widgets[1].Enabled {
+		t.Errorf("proc should be listed but disabled, got %+v", widgets[1])
+	}
+}
+
+func TestNewConfigService_AppendsNewModulesDisabled(t *testing.T) {
+	dir := t.TempDir()
+	existing := `{"widgets":[{"id":"cpu","enabled":true}]}`
+	if err := os.WriteFile(filepath.Join(dir, "config.json"), []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cs, err := NewConfigService(dir, map[string]Module{
+		"cpu": &stubModule{id: "cpu"},
+		"mem": &stubModule{id: "mem"},
+	})
+	if err != nil {
+		t.Fatalf("NewConfigService: %v", err)
+	}
+	widgets := cs.GetConfig().Widgets
+	if len(widgets) != 2 {
+		t.Fatalf("expected 2 widgets, got %d", len(widgets))
+	}
+	if widgets[1].ID != "mem" || widgets[1].Enabled {
+		t.Errorf("expected mem appended disabled, got %+v", widgets[1])
+	}
+}
+
 func TestBuildDefaultWidgets_EmptySchemaNoProps(t *testing.T) {
 	mods := map[string]Module{
 		"cpu": &stubModule{id: "cpu", schema: []protocol.ConfigSchema{}},
@@ -3,20 +3,56 @@ package modules
 import (
 	"fmt"
 	"glancehud/internal/protocol"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/load"
 )
 
+// CPU display modes.
+const (
+	cpuModeSparkline = "sparkline" // one series over time
+	cpuModePerCore   = "per_core"  // bar-list, one row per logical core
+	cpuModeBreakdown = "breakdown" // bar-list of user/system/iowait/steal
+	cpuModeSummary   = "summary"   // key-value: usage, frequency, load average
+)
+
+// cpuSeries lists the named series every mode can plot or alert on, besides
+// the dynamic per-core "coreN" keys.
+var cpuSeries = []protocol.SelectOption{
+	{Label: "Usage (%)", Value: "usage"},
+	{Label: "User (%)", Value: "user"},
+	{Label: "System (%)", Value: "system"},
+	{Label: "I/O Wait (%)", Value: "iowait"},
+	{Label: "Steal (%)", Value: "steal"},
+	{Label: "Frequency (MHz)", Value: "freq"},
+	{Label: "Load 1m", Value: "load1"},
+	{Label: "Load 5m", Value: "load5"},
+	{Label: "Load 15m", Value: "load15"},
+}
+
 type CPUModule struct {
 	minimalMode    bool
 	alertThreshold float64
+	mode           string
+	series         string // series plotted in sparkline mode
+	alertSeries    string // series compared against alertThreshold
+
+	prevTimes []cpu.TimesStat // per-core, from the previous Update
 }
 
 func NewCPUModule() *CPUModule {
 	return &CPUModule{
 		minimalMode:    false,
 		alertThreshold: 80,
+		mode:           cpuModeSparkline,
+		series:         "usage",
+		alertSeries:    "usage",
 	}
 }
 
@@ -35,13 +71,79 @@ func (m *CPUModule) ApplyConfig(props map[string]interface{}) {
 	if val, ok := props["alert_threshold"].(float64); ok {
 		m.alertThreshold = val
 	}
+	if val, ok := props["mode"].(string); ok {
+		switch val {
+		case cpuModeSparkline, cpuModePerCore, cpuModeBreakdown, cpuModeSummary:
+			m.mode = val
+		}
+	}
+	if val, ok := props["series"].(string); ok && isCPUSeries(val) {
+		m.series = val
+	}
+	if val, ok := props["alert_series"].(string); ok && isCPUSeries(val) {
+		m.alertSeries = val
+	}
+}
+
+func isCPUSeries(key string) bool {
+	if rest, ok := strings.CutPrefix(key, "core"); ok {
+		_, err := strconv.Atoi(rest)
+		return err == nil
+	}
+	for _, o := range cpuSeries {
+		if o.Value == key {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesOptions returns the static series plus one "coreN" per logical core.
+func seriesOptions() []protocol.SelectOption {
+	options := append([]protocol.SelectOption(nil), cpuSeries...)
+	if n, err := cpu.CountsWithContext(hostContext(), true); err == nil {
+		for i := 0; i < n; i++ {
+			options = append(options, protocol.SelectOption{
+				Label: fmt.Sprintf("Core %d (%%)", i),
+				Value: fmt.Sprintf("core%d", i),
+			})
+		}
+	}
+	return options
 }
 
 func (m *CPUModule) GetConfigSchema() []protocol.ConfigSchema {
+	series := seriesOptions()
 	return []protocol.ConfigSchema{
+		{
+			Name:    "mode",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: cpuModeSparkline,
+			Options: []protocol.SelectOption{
+				{Label: "Sparkline", Value: cpuModeSparkline},
+				{Label: "Per-core bars", Value: cpuModePerCore},
+				{Label: "Time breakdown", Value: cpuModeBreakdown},
+				{Label: "Usage / frequency / load", Value: cpuModeSummary},
+			},
+		},
+		{
+			Name:    "series",
+			Label:   "Sparkline Series",
+			Type:    protocol.ConfigSelect,
+			Default: "usage",
+			Options: series,
+		},
+		{
+			Name:    "alert_series",
+			Label:   "Alert Series",
+			Type:    protocol.ConfigSelect,
+			Default: "usage",
+			Options: series,
+		},
 		{
 			Name:    "alert_threshold",
-			Label:   "Alert Threshold (%)",
+			Label:   "Alert Threshold",
 			Type:    protocol.ConfigNumber,
 			Default: 80,
 		},
@@ -59,30 +161,85 @@ func (m *CPUModule) GetRenderConfig() protocol.RenderConfig {
 			},
 		}
 	}
+	switch m.mode {
+	case cpuModePerCore:
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.cpu",
+			Type:  protocol.TypeBarList,
+			Title: "CPU Cores",
+		}
+	case cpuModeBreakdown:
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.cpu",
+			Type:  protocol.TypeBarList,
+			Title: "CPU Time",
+		}
+	case cpuModeSummary:
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.cpu",
+			Type:  protocol.TypeKeyValue,
+			Title: "CPU",
+			Props: map[string]any{
+				"layout": "column",
+			},
+		}
+	}
+
+	title := "CPU"
+	if m.series != "usage" {
+		title = "CPU " + seriesLabel(m.series)
+	}
+	unit := seriesUnit(m.series)
+	props := map[string]any{
+		"unit":      unit,
+		"maxPoints": 60, // 60s of history at 1s interval
+	}
+	if unit != "%" {
+		props["color"] = NeutralColor
+	}
 	return protocol.RenderConfig{
 		ID:    "glancehud.core.cpu",
 		Type:  protocol.TypeSpark,
-		Title: "CPU",
-		Props: map[string]any{
-			"unit":      "%",
-			"maxPoints": 60, // 60s of history at 1s interval
-		},
+		Title: title,
+		Props: props,
+	}
+}
+
+func seriesLabel(key string) string {
+	switch key {
+	case "freq":
+		return "MHz"
+	case "load1", "load5", "load15":
+		return "Load " + strings.TrimPrefix(key, "load") + "m"
+	}
+	return key
+}
+
+func seriesUnit(key string) string {
+	switch key {
+	case "freq":
+		return " MHz"
+	case "load1", "load5", "load15":
+		return ""
 	}
+	return "%"
 }
 
 func (m *CPUModule) Update() (*protocol.DataPayload, error) {
-	cpuPercent, err := cpu.Percent(0, false)
+	times, err := cpu.TimesWithContext(hostContext(), true)
 	if err != nil {
 		return nil, err
 	}
-	usage := round(cpuPercent[0], 1)
+	series := m.collect(times)
+	usage := series["usage"]
 
 	payload := &protocol.DataPayload{
-		Value: usage,
+		Value:  series[m.series],
+		Series: m.shownSeries(series, len(times)),
 	}
 
-	// Alert: turn sparkline red when usage exceeds threshold
-	if usage > m.alertThreshold {
+	// Alert: turn the widget red when the chosen series exceeds threshold
+	if v, ok := series[m.alertSeries]; ok && v > m.alertThreshold {
 		payload.Props = map[string]any{
 			"color": "#ef4444",
 		}
@@ -90,10 +247,187 @@ func (m *CPUModule) Update() (*protocol.DataPayload, error) {
 
 	// Minimal mode: key-value list
 	if m.minimalMode {
+		payload.Value = usage
 		payload.Items = []protocol.KeyValueItem{
 			{Key: "CPU", Value: fmt.Sprintf("%.1f%%", usage), Icon: "Cpu"},
 		}
+		return payload, nil
+	}
+
+	switch m.mode {
+	case cpuModePerCore:
+		items := make([]protocol.BarListItem, 0, len(times))
+		for i := range times {
+			pct := series[fmt.Sprintf("core%d", i)]
+			items = append(items, protocol.BarListItem{
+				Label:   fmt.Sprintf("Core %d", i),
+				Percent: pct,
+				Value:   fmt.Sprintf("%.0f%%", pct),
+			})
+		}
+		payload.Items = items
+	case cpuModeBreakdown:
+		var items []protocol.BarListItem
+		for _, key := range []string{"user", "system", "iowait", "steal"} {
+			pct := series[key]
+			items = append(items, protocol.BarListItem{
+				Label:   key,
+				Percent: pct,
+				Value:   fmt.Sprintf("%.1f%%", pct),
+			})
+		}
+		payload.Items = items
+	case cpuModeSummary:
+		items := []protocol.KeyValueItem{
+			{Key: "Usage", Value: fmt.Sprintf("%.1f%%", usage), Icon: "Cpu"},
+		}
+		if freq, ok := series["freq"]; ok {
+			items = append(items, protocol.KeyValueItem{Key: "Freq", Value: fmt.Sprintf("%.0f MHz", freq), Icon: "Zap"})
+		}
+		if _, ok := series["load1"]; ok {
+			items = append(items, protocol.KeyValueItem{
+				Key:   "Load",
+				Value: fmt.Sprintf("%.2f %.2f %.2f", series["load1"], series["load5"], series["load15"]),
+				Icon:  "Activity",
+			})
+		}
+		payload.Items = items
 	}
 
 	return payload, nil
 }
+
+// shownSeries picks the series the current mode renders, plus the plotted
+// and alert series, so per-core values do not ride along on every update.
+func (m *CPUModule) shownSeries(series map[string]float64, cores int) map[string]float64 {
+	keys := []string{"usage", m.series, m.alertSeries}
+	switch {
+	case m.minimalMode:
+	case m.mode == cpuModePerCore:
+		for i := 0; i < cores; i++ {
+			keys = append(keys, fmt.Sprintf("core%d", i))
+		}
+	case m.mode == cpuModeBreakdown:
+		keys = append(keys, "user", "system", "iowait", "steal")
+	case m.mode == cpuModeSummary:
+		keys = append(keys, "freq", "load1", "load5", "load15")
+	}
+	out := make(map[string]float64, len(keys))
+	for _, k := range keys {
+		if v, ok := series[k]; ok {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// collect derives every series from the per-core CPU time delta since the
+// previous call, plus frequency and load average where the platform supports
+// them. The first call has no baseline, so percentages start at 0.
+func (m *CPUModule) collect(times []cpu.TimesStat) map[string]float64 {
+	series := make(map[string]float64, len(cpuSeries)+len(times))
+
+	var total, prevTotal cpu.TimesStat
+	for i, t := range times {
+		addTimes(&total, t)
+		if i < len(m.prevTimes) {
+			addTimes(&prevTotal, m.prevTimes[i])
+			series[fmt.Sprintf("core%d", i)] = round(busyPercent(m.prevTimes[i], t), 1)
+		} else {
+			series[fmt.Sprintf("core%d", i)] = 0
+		}
+	}
+
+	if len(m.prevTimes) == len(times) {
+		series["usage"] = round(busyPercent(prevTotal, total), 1)
+		span := totalTime(total) - totalTime(prevTotal)
+		share := func(cur, prev float64) float64 {
+			if span <= 0 {
+				return 0
+			}
+			return round(max(0, cur-prev)/span*100, 1)
+		}
+		series["user"] = share(total.User, prevTotal.User)
+		series["system"] = share(total.System, prevTotal.System)
+		series["iowait"] = share(total.Iowait, prevTotal.Iowait)
+		series["steal"] = share(total.Steal, prevTotal.Steal)
+	} else {
+		// First sample or core count changed (hotplug): no meaningful delta
+		for _, key := range []string{"usage", "user", "system", "iowait", "steal"} {
+			series[key] = 0
+		}
+	}
+	m.prevTimes = times
+
+	if freq, ok := currentFreqMHz(); ok {
+		series["freq"] = round(freq, 0)
+	}
+	if avg, err := load.AvgWithContext(hostContext()); err == nil {
+		series["load1"] = round(avg.Load1, 2)
+		series["load5"] = round(avg.Load5, 2)
+		series["load15"] = round(avg.Load15, 2)
+	}
+	return series
+}
+
+func addTimes(dst *cpu.TimesStat, t cpu.TimesStat) {
+	dst.User += t.User
+	dst.System += t.System
+	dst.Idle += t.Idle
+	dst.Nice += t.Nice
+	dst.Iowait += t.Iowait
+	dst.Irq += t.Irq
+	dst.Softirq += t.Softirq
+	dst.Steal += t.Steal
+}
+
+// totalTime sums all CPU states. Guest time is already included in User on
+// Linux, so it is not added again.
+func totalTime(t cpu.TimesStat) float64 {
+	return t.User + t.System + t.Idle + t.Nice + t.Iowait + t.Irq + t.Softirq + t.Steal
+}
+
+func busyPercent(prev, cur cpu.TimesStat) float64 {
+	span := totalTime(cur) - totalTime(prev)
+	if span <= 0 {
+		return 0
+	}
+	idle := (cur.Idle + cur.Iowait) - (prev.Idle + prev.Iowait)
+	return min(100, max(0, (span-idle)/span*100))
+}
+
+// nominalFreqMHz is looked up once: cpu.Info goes through WMI on Windows,
+// which is far too slow to call every second.
+var nominalFreqMHz = sync.OnceValue(func() float64 {
+	infos, err := cpu.InfoWithContext(hostContext())
+	if err != nil || len(infos) == 0 {
+		return 0
+	}
+	return infos[0].Mhz
+})
+
+// currentFreqMHz averages scaling_cur_freq across cores on Linux and falls
+// back to the nominal clock from cpu.Info elsewhere.
+func currentFreqMHz() (float64, bool) {
+	paths, _ := filepath.Glob(hostSys("devices/system/cpu/cpu[0-9]*/cpufreq/scaling_cur_freq"))
+	var sum float64
+	var n int
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		khz, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+		if err != nil {
+			continue
+		}
+		sum += khz / 1000
+		n++
+	}
+	if n > 0 {
+		return sum / float64(n), true
+	}
+
+	mhz := nominalFreqMHz()
+	return mhz, mhz > 0
+}
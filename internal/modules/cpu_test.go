@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+)
+
+// --- busyPercent ---
+
+func TestBusyPercent(t *testing.T) {
+	prev := cpu.TimesStat{User: 100, System: 50, Idle: 800, Iowait: 50}
+	cur := cpu.TimesStat{User: 130, System: 60, Idle: 850, Iowait: 60}
+	// span 100, idle+iowait delta 60 → 40% busy
+	if got := busyPercent(prev, cur); got != 40 {
+		t.Errorf("want 40, got %v", got)
+	}
+	if got := busyPercent(cur, cur); got != 0 {
+		t.Errorf("no elapsed time should report 0, got %v", got)
+	}
+}
+
+// --- collect ---
+
+func TestCPUModule_Collect(t *testing.T) {
+	m := NewCPUModule()
+	first := m.collect([]cpu.TimesStat{
+		{User: 100, Idle: 100},
+		{User: 100, Idle: 100},
+	})
+	if first["usage"] != 0 || first["core0"] != 0 || first["core1"] != 0 {
+		t.Errorf("first sample should report 0, got %v", first)
+	}
+
+	got := m.collect([]cpu.TimesStat{
+		{User: 150, System: 10, Idle: 130, Iowait: 10}, // 60 busy of 100
+		{User: 110, Steal: 10, Idle: 180},              // 20 busy of 100
+	})
+	want := map[string]float64{
+		"core0": 60, "core1": 20, "usage": 40,
+		"user": 30, "system": 5, "iowait": 5, "steal": 5,
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: want %v, got %v", k, v, got[k])
+		}
+	}
+}
+
+func TestCPUModule_Collect_CoreCountChange(t *testing.T) {
+	m := NewCPUModule()
+	m.collect([]cpu.TimesStat{{User: 100, Idle: 100}})
+	got := m.collect([]cpu.TimesStat{{User: 200, Idle: 100}, {User: 50, Idle: 50}})
+	if got["usage"] != 0 {
+		t.Errorf("core hotplug should reset aggregate, got %v", got["usage"])
+	}
+	if got["core0"] != 100 || got["core1"] != 0 {
+		t.Errorf("want core0=100 core1=0, got %v %v", got["core0"], got["core1"])
+	}
+}
+
+// --- ApplyConfig ---
+
+func TestCPUModule_ApplyConfig_Series(t *testing.T) {
+	m := NewCPUModule()
+	m.ApplyConfig(map[string]interface{}{"series": "load5", "alert_series": "core3", "mode": "bogus"})
+	if m.series != "load5" || m.alertSeries != "core3" {
+		t.Errorf("want load5/core3, got %s/%s", m.series, m.alertSeries)
+	}
+	if m.mode != cpuModeSparkline {
+		t.Errorf("unknown mode should be ignored, got %s", m.mode)
+	}
+	m.ApplyConfig(map[string]interface{}{"series": "corex"})
+	if m.series != "load5" {
+		t.Errorf("invalid series should be ignored, got %s", m.series)
+	}
+}
+
+func TestCPUModule_SparklineColor(t *testing.T) {
+	m := NewCPUModule()
+	if _, ok := m.GetRenderConfig().Props["color"]; ok {
+		t.Error("usage should keep threshold coloring")
+	}
+	for _, series := range []string{"freq", "load1"} {
+		m.ApplyConfig(map[string]interface{}{"series": series})
+		if c := m.GetRenderConfig().Props["color"]; c != NeutralColor {
+			t.Errorf("%s: want neutral color, got %v", series, c)
+		}
+	}
+}
+
+func TestCPUModule_ShownSeries(t *testing.T) {
+	series := map[string]float64{
+		"usage": 10, "user": 6, "system": 4, "iowait": 0, "steal": 0,
+		"freq": 2400, "load1": 1, "load5": 1, "load15": 1,
+		"core0": 12, "core1": 8,
+	}
+	m := NewCPUModule()
+	m.ApplyConfig(map[string]interface{}{"alert_series": "core1"})
+	if got := m.shownSeries(series, 2); len(got) != 2 || got["core1"] != 8 {
+		t.Errorf("sparkline: want usage and alert series only, got %v", got)
+	}
+	m.ApplyConfig(map[string]interface{}{"mode": "per_core"})
+	if got := m.shownSeries(series, 2); len(got) != 3 || got["core0"] != 12 {
+		t.Errorf("per_core: want usage and cores, got %v", got)
+	}
+	m.ApplyConfig(map[string]interface{}{"mode": "summary"})
+	if got := m.shownSeries(series, 2); len(got) != 6 || got["freq"] != 2400 {
+		t.Errorf("summary: want usage, alert series, freq and load, got %v", got)
+	}
+}
@@ -9,13 +9,42 @@ import (
 	"github.com/shirou/gopsutil/v4/disk"
 )
 
+// Inode row display modes.
+const (
+	inodesAuto   = "auto"   // only mounts at or above the warning threshold
+	inodesAlways = "always" // every mount that reports inodes
+	inodesOff    = "off"
+)
+
+// maxForecast is the longest time-to-full worth showing; slower growth is
+// indistinguishable from noise over a short window.
+const maxForecast = 30 * 24 * time.Hour
+
 type DiskModule struct {
-	selectedPaths []string // Empty means auto-detect all
-	minimalMode   bool
+	selectedPaths  []string // Empty means auto-detect all
+	minimalMode    bool
+	forecastWindow time.Duration
+	inodes         string
+	inodeWarn      float64
+	inodeCritical  float64
+
+	usage map[string][]usageSample // per-mount used bytes, oldest first
+}
+
+// usageSample is one used-bytes reading for a mount.
+type usageSample struct {
+	at   time.Time
+	used float64
 }
 
 func NewDiskModule(path string) *DiskModule {
-	return &DiskModule{}
+	return &DiskModule{
+		forecastWindow: time.Hour,
+		inodes:         inodesAuto,
+		inodeWarn:      70,
+		inodeCritical:  90,
+		usage:          make(map[string][]usageSample),
+	}
 }
 
 func (m *DiskModule) ID() string {
@@ -39,6 +68,18 @@ func (m *DiskModule) ApplyConfig(props map[string]interface{}) {
 	if val, ok := props["minimal_mode"].(bool); ok {
 		m.minimalMode = val
 	}
+	if val, ok := props["forecast_window"].(float64); ok && val > 0 {
+		m.forecastWindow = time.Duration(val * float64(time.Minute))
+	}
+	if val, ok := props["inodes"].(string); ok && (val == inodesAuto || val == inodesAlways || val == inodesOff) {
+		m.inodes = val
+	}
+	if val, ok := props["inode_warn"].(float64); ok {
+		m.inodeWarn = val
+	}
+	if val, ok := props["inode_critical"].(float64); ok {
+		m.inodeCritical = val
+	}
 }
 
 func (m *DiskModule) GetConfigSchema() []protocol.ConfigSchema {
@@ -58,12 +99,41 @@ func (m *DiskModule) GetConfigSchema() []protocol.ConfigSchema {
 			Default: defaults,
 			Options: options,
 		},
+		{
+			Name:    "forecast_window",
+			Label:   "Time-to-full Window (min)",
+			Type:    protocol.ConfigNumber,
+			Default: 60,
+		},
+		{
+			Name:    "inodes",
+			Label:   "Inode Usage",
+			Type:    protocol.ConfigSelect,
+			Default: inodesAuto,
+			Options: []protocol.SelectOption{
+				{Label: "Show when above warning", Value: inodesAuto},
+				{Label: "Always show", Value: inodesAlways},
+				{Label: "Hide", Value: inodesOff},
+			},
+		},
+		{
+			Name:    "inode_warn",
+			Label:   "Inode Warning (%)",
+			Type:    protocol.ConfigNumber,
+			Default: 70,
+		},
+		{
+			Name:    "inode_critical",
+			Label:   "Inode Critical (%)",
+			Type:    protocol.ConfigNumber,
+			Default: 90,
+		},
 	}
 }
 
 func discoverPartitions() []protocol.SelectOption {
 	var options []protocol.SelectOption
-	partitions, err := disk.Partitions(false)
+	partitions, err := disk.PartitionsWithContext(hostContext(), false)
 	if err != nil {
 		return options
 	}
@@ -102,18 +172,35 @@ func (m *DiskModule) GetRenderConfig() protocol.RenderConfig {
 
 func (m *DiskModule) Update() (*protocol.DataPayload, error) {
 	paths := m.resolvePaths()
+	now := time.Now()
+
+	stats := make(map[string]*disk.UsageStat, len(paths))
+	for _, p := range paths {
+		// Mountpoints are host-relative; stat them under the host root
+		diskStat, err := disk.UsageWithContext(hostContext(), hostRoot(p))
+		if err != nil {
+			continue
+		}
+		stats[p] = diskStat
+		m.recordUsage(p, float64(diskStat.Used), now)
+	}
+	m.pruneUsage(stats)
 
 	// Minimal Mode Items (KeyValue)
 	if m.minimalMode {
 		var items []protocol.KeyValueItem
 		for _, p := range paths {
-			diskStat, err := disk.Usage(p)
-			if err != nil {
+			diskStat, ok := stats[p]
+			if !ok {
 				continue
 			}
+			value := fmt.Sprintf("%.0f%%", diskStat.UsedPercent)
+			if ttf, ok := m.timeToFull(p, float64(diskStat.Free)); ok {
+				value += " ~" + formatDuration(ttf)
+			}
 			items = append(items, protocol.KeyValueItem{
 				Key:   p,
-				Value: fmt.Sprintf("%.0f%%", diskStat.UsedPercent),
+				Value: value,
 				Icon:  "HardDrive",
 			})
 		}
@@ -123,24 +210,126 @@ func (m *DiskModule) Update() (*protocol.DataPayload, error) {
 	// BarList Items
 	var items []protocol.BarListItem
 	for _, p := range paths {
-		diskStat, err := disk.Usage(p)
-		if err != nil {
+		diskStat, ok := stats[p]
+		if !ok {
 			continue
 		}
 
 		total := round(float64(diskStat.Total)/1024/1024/1024, 0)
 		used := round(float64(diskStat.Used)/1024/1024/1024, 1)
 
+		value := fmt.Sprintf("%.1f / %.0f GB", used, total)
+		if ttf, ok := m.timeToFull(p, float64(diskStat.Free)); ok {
+			value += fmt.Sprintf(" · ~%s to full", formatDuration(ttf))
+		}
+
 		items = append(items, protocol.BarListItem{
 			Label:   p,
 			Percent: round(diskStat.UsedPercent, 1),
-			Value:   fmt.Sprintf("%.1f / %.0f GB", used, total),
+			Value:   value,
 		})
+
+		if item, ok := m.inodeItem(p, diskStat); ok {
+			items = append(items, item)
+		}
 	}
 
 	return &protocol.DataPayload{Items: items}, nil
 }
 
+// inodeItem builds the inode row for a mount, colored by the module's own
+// inode thresholds rather than the generic percent scale. Filesystems without
+// inodes (Windows, some FUSE mounts) report zero and are skipped.
+func (m *DiskModule) inodeItem(path string, st *disk.UsageStat) (protocol.BarListItem, bool) {
+	if m.inodes == inodesOff || st.InodesTotal == 0 {
+		return protocol.BarListItem{}, false
+	}
+	pct := st.InodesUsedPercent
+	if m.inodes == inodesAuto && pct < m.inodeWarn {
+		return protocol.BarListItem{}, false
+	}
+
+	color := "#22c55e"
+	switch {
+	case pct >= m.inodeCritical:
+		color = "#ef4444"
+	case pct >= m.inodeWarn:
+		color = "#f59e0b"
+	}
+	return protocol.BarListItem{
+		Label:   path + " inodes",
+		Percent: round(pct, 1),
+		Value:   fmt.Sprintf("%s / %s", formatCount(st.InodesUsed), formatCount(st.InodesTotal)),
+		Color:   color,
+	}, true
+}
+
+// recordUsage appends a used-bytes sample and drops samples older than the
+// forecast window.
+func (m *DiskModule) recordUsage(path string, used float64, now time.Time) {
+	samples := append(m.usage[path], usageSample{at: now, used: used})
+	cutoff := now.Add(-m.forecastWindow)
+	i := 0
+	for i < len(samples) && samples[i].at.Before(cutoff) {
+		i++
+	}
+	m.usage[path] = samples[i:]
+}
+
+// pruneUsage forgets mounts that are no longer shown.
+func (m *DiskModule) pruneUsage(current map[string]*disk.UsageStat) {
+	for p := range m.usage {
+		if _, ok := current[p]; !ok {
+			delete(m.usage, p)
+		}
+	}
+}
+
+// timeToFull fits a least-squares line through the mount's usage samples and
+// extrapolates when used space reaches used+free. It reports false while
+// there is too little data, when usage is flat or shrinking, or when the
+// estimate exceeds maxForecast.
+func (m *DiskModule) timeToFull(path string, free float64) (time.Duration, bool) {
+	samples := m.usage[path]
+	if len(samples) < 3 {
+		return 0, false
+	}
+	// Require the samples to cover a meaningful part of the window
+	span := samples[len(samples)-1].at.Sub(samples[0].at)
+	if span < time.Minute || span < m.forecastWindow/10 {
+		return 0, false
+	}
+
+	slope := usageSlope(samples) // bytes per second
+	if slope <= 0 {
+		return 0, false
+	}
+	secs := free / slope
+	if secs > maxForecast.Seconds() {
+		return 0, false
+	}
+	return time.Duration(secs * float64(time.Second)), true
+}
+
+// usageSlope returns the least-squares slope of used bytes over time.
+func usageSlope(samples []usageSample) float64 {
+	t0 := samples[0].at
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, s := range samples {
+		x := s.at.Sub(t0).Seconds()
+		sumX += x
+		sumY += s.used
+		sumXY += x * s.used
+		sumXX += x * x
+	}
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denom
+}
+
 func (m *DiskModule) resolvePaths() []string {
 	if len(m.selectedPaths) > 0 {
 		return m.selectedPaths
@@ -148,7 +337,7 @@ func (m *DiskModule) resolvePaths() []string {
 
 	// Auto detect all physical partitions
 	var paths []string
-	partitions, err := disk.Partitions(false)
+	partitions, err := disk.PartitionsWithContext(hostContext(), false)
 	if err == nil {
 		for _, p := range partitions {
 			if strings.HasPrefix(p.Mountpoint, "/snap") || strings.HasPrefix(p.Mountpoint, "/loop") {
@@ -0,0 +1,95 @@
+package modules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+const gib = 1 << 30
+
+// --- Time-to-full ---
+
+func TestDiskModule_TimeToFull_LinearGrowth(t *testing.T) {
+	m := NewDiskModule("")
+	t0 := time.Unix(1000, 0)
+	// 1 GiB per 10 minutes
+	for i := 0; i <= 6; i++ {
+		m.recordUsage("/", float64(10*gib+i*gib), t0.Add(time.Duration(i)*10*time.Minute))
+	}
+
+	// 18 GiB free at 1 GiB / 10 min → 3h
+	ttf, ok := m.timeToFull("/", 18*gib)
+	if !ok {
+		t.Fatal("expected a forecast")
+	}
+	if ttf < 179*time.Minute || ttf > 181*time.Minute {
+		t.Errorf("want ~3h, got %v", ttf)
+	}
+	if got := formatDuration(ttf); got != "3h" {
+		t.Errorf("want %q, got %q", "3h", got)
+	}
+}
+
+func TestDiskModule_TimeToFull_NoForecast(t *testing.T) {
+	t0 := time.Unix(1000, 0)
+	cases := map[string][]float64{
+		"flat":      {10 * gib, 10 * gib, 10 * gib, 10 * gib},
+		"shrinking": {10 * gib, 9 * gib, 8 * gib, 7 * gib},
+		"too few":   {10 * gib, 11 * gib},
+		"too slow":  {10 * gib, 10*gib + 1, 10*gib + 2, 10*gib + 3},
+	}
+	for name, used := range cases {
+		m := NewDiskModule("")
+		for i, u := range used {
+			m.recordUsage("/", u, t0.Add(time.Duration(i)*10*time.Minute))
+		}
+		if ttf, ok := m.timeToFull("/", 100*gib); ok {
+			t.Errorf("%s: want no forecast, got %v", name, ttf)
+		}
+	}
+}
+
+func TestDiskModule_RecordUsage_TrimsToWindow(t *testing.T) {
+	m := NewDiskModule("")
+	m.ApplyConfig(map[string]interface{}{"forecast_window": float64(30)})
+	t0 := time.Unix(1000, 0)
+	for i := 0; i < 10; i++ {
+		m.recordUsage("/", float64(i), t0.Add(time.Duration(i)*10*time.Minute))
+	}
+	// Window is 30 min: samples at 60, 70, 80, 90 min remain
+	if n := len(m.usage["/"]); n != 4 {
+		t.Errorf("want 4 samples in window, got %d", n)
+	}
+}
+
+// --- Inodes ---
+
+func TestDiskModule_InodeItem(t *testing.T) {
+	m := NewDiskModule("")
+	st := &disk.UsageStat{InodesTotal: 1_000_000, InodesUsed: 950_000, InodesUsedPercent: 95}
+
+	item, ok := m.inodeItem("/", st)
+	if !ok {
+		t.Fatal("expected inode row above warning threshold")
+	}
+	if item.Color != "#ef4444" || item.Label != "/ inodes" || item.Value != "950.0k / 1.0M" {
+		t.Errorf("unexpected item: %+v", item)
+	}
+
+	st.InodesUsedPercent = 50
+	if _, ok := m.inodeItem("/", st); ok {
+		t.Error("auto mode should hide inode row below warning threshold")
+	}
+
+	m.ApplyConfig(map[string]interface{}{"inodes": "always"})
+	if item, ok := m.inodeItem("/", st); !ok || item.Color != "#22c55e" {
+		t.Errorf("always mode: want healthy inode row, got %+v (ok=%v)", item, ok)
+	}
+
+	st.InodesTotal = 0
+	if _, ok := m.inodeItem("/", st); ok {
+		t.Error("filesystems without inodes should be skipped")
+	}
+}
@@ -0,0 +1,281 @@
+package modules
+
+import (
+	"fmt"
+	"glancehud/internal/protocol"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// pseudoDevicePrefixes are left unchecked by default: loop mounts, RAM disks
+// and optical drives rarely matter for "is the disk saturated?".
+var pseudoDevicePrefixes = []string{"loop", "ram", "zram", "sr", "fd"}
+
+// DiskIOModule reports per-device throughput, IOPS and busy percentage.
+type DiskIOModule struct {
+	selectedDevices []string // Empty means auto-detect whole disks
+	display         string   // "bar-list" | "sparkline"
+
+	prev map[string]deviceSample
+}
+
+// deviceSample is the last seen counter reading for one block device.
+type deviceSample struct {
+	readBytes, writeBytes uint64
+	ops                   uint64 // reads + writes completed
+	ioTime                uint64 // ms spent doing I/O
+	at                    time.Time
+}
+
+// deviceRate is the computed activity of one device over the last interval.
+type deviceRate struct {
+	name              string
+	readBps, writeBps float64
+	iops              float64
+	busy              float64 // 0~100
+}
+
+func NewDiskIOModule() *DiskIOModule {
+	return &DiskIOModule{
+		display: "bar-list",
+		prev:    make(map[string]deviceSample),
+	}
+}
+
+func (m *DiskIOModule) ID() string {
+	return "diskio"
+}
+
+func (m *DiskIOModule) Interval() time.Duration {
+	return 2 * time.Second
+}
+
+func (m *DiskIOModule) ApplyConfig(props map[string]interface{}) {
+	// "devices" is a []interface{} from JSON deserialization
+	if val, ok := props["devices"].([]interface{}); ok {
+		m.selectedDevices = nil
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				m.selectedDevices = append(m.selectedDevices, s)
+			}
+		}
+	}
+	if val, ok := props["display"].(string); ok && (val == "bar-list" || val == "sparkline") {
+		m.display = val
+	}
+}
+
+func (m *DiskIOModule) GetConfigSchema() []protocol.ConfigSchema {
+	options := discoverBlockDevices()
+
+	// Default: whole physical disks checked
+	names := make([]string, 0, len(options))
+	for _, o := range options {
+		names = append(names, o.Value)
+	}
+	defaults := wholeDisks(names)
+
+	return []protocol.ConfigSchema{
+		{
+			Name:    "devices",
+			Label:   "顯示裝置",
+			Type:    protocol.ConfigCheckboxes,
+			Default: defaults,
+			Options: options,
+		},
+		{
+			Name:    "display",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: "bar-list",
+			Options: []protocol.SelectOption{
+				{Label: "Per-device busy %", Value: "bar-list"},
+				{Label: "Sparkline (read / write)", Value: "sparkline"},
+			},
+		},
+	}
+}
+
+func discoverBlockDevices() []protocol.SelectOption {
+	var options []protocol.SelectOption
+	counters, err := disk.IOCountersWithContext(hostContext())
+	if err != nil {
+		return options
+	}
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		options = append(options, protocol.SelectOption{
+			Label: name,
+			Value: name,
+		})
+	}
+	return options
+}
+
+// wholeDisks drops pseudo devices and partitions whose parent disk is also
+// listed (sda1 when sda exists, nvme0n1p2 when nvme0n1 exists), so traffic is
+// not counted twice.
+func wholeDisks(names []string) []string {
+	var out []string
+	for _, name := range names {
+		if isPseudoDevice(name) || isPartition(name, names) {
+			continue
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+func isPseudoDevice(name string) bool {
+	for _, p := range pseudoDevicePrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPartition reports whether name is a numbered partition of another listed
+// device. Parents ending in a digit separate the number with "p" (nvme0n1p2,
+// md127p1), so dm-10 is not read as a partition of dm-1.
+func isPartition(name string, all []string) bool {
+	for _, parent := range all {
+		if parent == name || !strings.HasPrefix(name, parent) {
+			continue
+		}
+		suffix := name[len(parent):]
+		if last := parent[len(parent)-1]; last >= '0' && last <= '9' {
+			var ok bool
+			if suffix, ok = strings.CutPrefix(suffix, "p"); !ok {
+				continue
+			}
+		}
+		if suffix != "" && strings.Trim(suffix, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *DiskIOModule) GetRenderConfig() protocol.RenderConfig {
+	if m.display == "sparkline" {
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.diskio",
+			Type:  protocol.TypeSpark,
+			Title: "Disk I/O",
+			Props: map[string]any{
+				"maxPoints": 60,
+				"series": []map[string]any{
+					{"key": "read", "label": "R", "color": "#22c55e"},
+					{"key": "write", "label": "W", "color": "#f59e0b"},
+				},
+			},
+		}
+	}
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.diskio",
+		Type:  protocol.TypeBarList,
+		Title: "Disk I/O",
+		Props: map[string]any{
+			"headers": []string{"Device", "Busy", "Throughput"},
+		},
+	}
+}
+
+func (m *DiskIOModule) Update() (*protocol.DataPayload, error) {
+	counters, err := disk.IOCountersWithContext(hostContext())
+	if err != nil {
+		return nil, err
+	}
+	rates := m.rates(counters, time.Now())
+
+	if m.display == "sparkline" {
+		var read, write, iops, busiest float64
+		for _, r := range rates {
+			read += r.readBps
+			write += r.writeBps
+			iops += r.iops
+			busiest = max(busiest, r.busy)
+		}
+		return &protocol.DataPayload{
+			Value: round(busiest, 1),
+			DisplayValue: fmt.Sprintf("R %s W %s · %.0f IOPS · %.0f%%",
+				formatRate(read, false), formatRate(write, false), iops, busiest),
+			Series: map[string]float64{
+				"read":  round(read, 0),
+				"write": round(write, 0),
+			},
+		}, nil
+	}
+
+	items := make([]protocol.BarListItem, 0, len(rates))
+	for _, r := range rates {
+		items = append(items, protocol.BarListItem{
+			Label:   r.name,
+			Percent: round(r.busy, 1),
+			Value: fmt.Sprintf("R %s W %s · %.0f IOPS",
+				formatRate(r.readBps, false), formatRate(r.writeBps, false), r.iops),
+		})
+	}
+	return &protocol.DataPayload{Items: items}, nil
+}
+
+// rates computes per-device activity for the selected devices. As with
+// NetModule, each device keeps its own baseline so newly selected devices
+// report 0 on their first sample rather than a lifetime-counter spike.
+func (m *DiskIOModule) rates(counters map[string]disk.IOCountersStat, now time.Time) []deviceRate {
+	selected := m.resolveDevices(counters)
+
+	next := make(map[string]deviceSample, len(selected))
+	rates := make([]deviceRate, 0, len(selected))
+	for _, name := range selected {
+		c, ok := counters[name]
+		if !ok {
+			continue
+		}
+		cur := deviceSample{
+			readBytes:  c.ReadBytes,
+			writeBytes: c.WriteBytes,
+			ops:        c.ReadCount + c.WriteCount,
+			ioTime:     c.IoTime,
+			at:         now,
+		}
+		next[name] = cur
+
+		r := deviceRate{name: name}
+		if prev, ok := m.prev[name]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+			if elapsed > 0 && cur.readBytes >= prev.readBytes && cur.writeBytes >= prev.writeBytes &&
+				cur.ops >= prev.ops && cur.ioTime >= prev.ioTime {
+				r.readBps = float64(cur.readBytes-prev.readBytes) / elapsed
+				r.writeBps = float64(cur.writeBytes-prev.writeBytes) / elapsed
+				r.iops = float64(cur.ops-prev.ops) / elapsed
+				r.busy = min(100, float64(cur.ioTime-prev.ioTime)/(elapsed*1000)*100)
+			}
+		}
+		rates = append(rates, r)
+	}
+	m.prev = next
+	return rates
+}
+
+func (m *DiskIOModule) resolveDevices(counters map[string]disk.IOCountersStat) []string {
+	if len(m.selectedDevices) > 0 {
+		return m.selectedDevices
+	}
+
+	// Auto detect whole disks
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return wholeDisks(names)
+}
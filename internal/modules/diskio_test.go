@@ -0,0 +1,85 @@
+package modules
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// --- Device discovery ---
+
+func TestWholeDisks(t *testing.T) {
+	names := []string{"loop0", "nvme0n1", "nvme0n1p1", "nvme0n1p2", "sda", "sda1", "sdb", "dm-0", "zram0"}
+	got := wholeDisks(names)
+	want := []string{"nvme0n1", "sda", "sdb", "dm-0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	// Numbered siblings of a parent ending in a digit are whole disks
+	names = []string{"dm-1", "dm-10", "md1", "md127", "md127p1", "nvme0n1", "nvme0n10", "nvme0n10p1"}
+	got = wholeDisks(names)
+	want = []string{"dm-1", "dm-10", "md1", "md127", "nvme0n1", "nvme0n10"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+// --- rates ---
+
+func TestDiskIOModule_Rates(t *testing.T) {
+	m := NewDiskIOModule()
+	t0 := time.Unix(1000, 0)
+	counters := map[string]disk.IOCountersStat{
+		"sda":  {Name: "sda", ReadBytes: 1000, WriteBytes: 2000, ReadCount: 10, WriteCount: 10, IoTime: 100},
+		"sda1": {Name: "sda1", ReadBytes: 1000, WriteBytes: 2000},
+	}
+
+	first := m.rates(counters, t0)
+	if len(first) != 1 || first[0].name != "sda" {
+		t.Fatalf("want only sda, got %+v", first)
+	}
+	if first[0].busy != 0 || first[0].iops != 0 {
+		t.Errorf("first sample should report 0, got %+v", first[0])
+	}
+
+	counters["sda"] = disk.IOCountersStat{
+		Name: "sda", ReadBytes: 5000, WriteBytes: 10000, ReadCount: 30, WriteCount: 50, IoTime: 1100,
+	}
+	got := m.rates(counters, t0.Add(2*time.Second))[0]
+	if got.readBps != 2000 || got.writeBps != 4000 {
+		t.Errorf("want read=2000 write=4000 B/s, got %+v", got)
+	}
+	if got.iops != 30 {
+		t.Errorf("want 30 IOPS, got %v", got.iops)
+	}
+	if got.busy != 50 {
+		t.Errorf("want 50%% busy, got %v", got.busy)
+	}
+}
+
+func TestDiskIOModule_Rates_NewDeviceNoSpike(t *testing.T) {
+	m := NewDiskIOModule()
+	t0 := time.Unix(1000, 0)
+	counters := map[string]disk.IOCountersStat{
+		"sda": {Name: "sda", ReadBytes: 1 << 40, IoTime: 1 << 30},
+	}
+	m.ApplyConfig(map[string]interface{}{"devices": []interface{}{"sda"}})
+
+	got := m.rates(counters, t0)
+	if got[0].readBps != 0 || got[0].busy != 0 {
+		t.Errorf("newly selected device should report 0, got %+v", got[0])
+	}
+}
+
+func TestDiskIOModule_Rates_BusyClamped(t *testing.T) {
+	m := NewDiskIOModule()
+	t0 := time.Unix(1000, 0)
+	m.rates(map[string]disk.IOCountersStat{"sda": {IoTime: 0}}, t0)
+	got := m.rates(map[string]disk.IOCountersStat{"sda": {IoTime: 5000}}, t0.Add(time.Second))
+	if got[0].busy != 100 {
+		t.Errorf("want busy clamped to 100, got %v", got[0].busy)
+	}
+}
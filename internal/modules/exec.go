@@ -0,0 +1,325 @@
+package modules
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"glancehud/internal/protocol"
+	"maps"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Exec output parsers.
+const (
+	execParseNumber = "number" // first field of stdout is the value
+	execParseRegex  = "regex"  // named groups of Pattern
+	execParseJSON   = "json"   // stdout is JSON; Fields maps paths into the payload
+	execParseLines  = "lines"  // one "label value" row per line
+)
+
+const (
+	defaultExecInterval = 10 * time.Second
+	defaultExecTimeout  = 5 * time.Second
+)
+
+var execNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ExecWidgetID returns the widget ID of the exec entry called name.
+func ExecWidgetID(name string) string {
+	return "exec." + name
+}
+
+// ExecModule runs one configured command on its own interval and turns its
+// output into a widget. A failed run (non-zero exit, timeout, unparsable
+// output) keeps the last good data and reports the reason in props.error.
+type ExecModule struct {
+	cfg      ExecConfig
+	typ      protocol.ComponentType
+	interval time.Duration
+	timeout  time.Duration
+	re       *regexp.Regexp // regex parser
+	mapping  *JSONMapping   // json parser
+
+	last *protocol.DataPayload
+}
+
+// NewExecModule validates cfg and builds its module.
+func NewExecModule(cfg ExecConfig) (*ExecModule, error) {
+	if !execNamePattern.MatchString(cfg.Name) {
+		return nil, fmt.Errorf("exec name %q must be letters, digits, '-' or '_'", cfg.Name)
+	}
+	if len(cfg.Command) == 0 || cfg.Command[0] == "" {
+		return nil, fmt.Errorf("exec %s: command required", cfg.Name)
+	}
+	m := &ExecModule{
+		cfg:      cfg,
+		typ:      protocol.ComponentType(cfg.Type),
+		interval: defaultExecInterval,
+		timeout:  defaultExecTimeout,
+	}
+	if m.typ == "" {
+		m.typ = protocol.TypeSpark
+	}
+	switch m.typ {
+	case protocol.TypeSpark, protocol.TypeGauge, protocol.TypeBarList, protocol.TypeKeyValue:
+	default:
+		return nil, fmt.Errorf("exec %s: unsupported type %q", cfg.Name, cfg.Type)
+	}
+
+	var err error
+	if cfg.Interval != "" {
+		if m.interval, err = time.ParseDuration(cfg.Interval); err != nil || m.interval < time.Second {
+			return nil, fmt.Errorf("exec %s: interval must be a duration of at least 1s", cfg.Name)
+		}
+	}
+	if cfg.Timeout != "" {
+		if m.timeout, err = time.ParseDuration(cfg.Timeout); err != nil || m.timeout <= 0 {
+			return nil, fmt.Errorf("exec %s: invalid timeout %q", cfg.Name, cfg.Timeout)
+		}
+	}
+
+	switch cfg.Parser {
+	case "", execParseNumber, execParseLines:
+	case execParseRegex:
+		if m.re, err = regexp.Compile(cfg.Pattern); err != nil {
+			return nil, fmt.Errorf("exec %s: %w", cfg.Name, err)
+		}
+		if !slices.ContainsFunc(m.re.SubexpNames(), func(n string) bool { return n != "" }) {
+			return nil, fmt.Errorf("exec %s: pattern has no named groups", cfg.Name)
+		}
+	case execParseJSON:
+		if m.mapping, err = NewJSONMapping(cfg.Fields); err != nil {
+			return nil, fmt.Errorf("exec %s: %w", cfg.Name, err)
+		}
+	default:
+		return nil, fmt.Errorf("exec %s: unknown parser %q", cfg.Name, cfg.Parser)
+	}
+	return m, nil
+}
+
+// Config returns the entry the module was built from.
+func (m *ExecModule) Config() ExecConfig {
+	return m.cfg
+}
+
+func (m *ExecModule) ID() string {
+	return ExecWidgetID(m.cfg.Name)
+}
+
+func (m *ExecModule) Interval() time.Duration {
+	return m.interval
+}
+
+// ApplyConfig is a no-op: exec widgets are configured in AppConfig.Exec.
+func (m *ExecModule) ApplyConfig(props map[string]interface{}) {}
+
+func (m *ExecModule) GetConfigSchema() []protocol.ConfigSchema {
+	return nil
+}
+
+func (m *ExecModule) GetRenderConfig() protocol.RenderConfig {
+	title := m.cfg.Title
+	if title == "" {
+		title = m.cfg.Name
+	}
+	props := maps.Clone(m.cfg.Props)
+	if m.typ == protocol.TypeKeyValue {
+		if props == nil {
+			props = make(map[string]any)
+		}
+		if _, ok := props["layout"]; !ok {
+			props["layout"] = "column"
+		}
+	}
+	return protocol.RenderConfig{
+		ID:    "glancehud.exec." + m.cfg.Name,
+		Type:  m.typ,
+		Title: title,
+		Props: props,
+	}
+}
+
+func (m *ExecModule) Update() (*protocol.DataPayload, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	out, err := runExec(ctx, m.cfg.Command)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", m.timeout)
+	}
+	var payload *protocol.DataPayload
+	if err == nil {
+		payload, err = m.parse(out)
+	}
+	if err != nil {
+		return m.errorPayload(err), nil
+	}
+	m.last = payload
+	return payload, nil
+}
+
+// runExec runs argv and returns its stdout. A non-zero exit becomes an error
+// carrying the last line of stderr.
+func runExec(ctx context.Context, argv []string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// A killed shell can leave children holding the pipes open
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		msg := fmt.Sprintf("exit status %d", exitErr.ExitCode())
+		if line := lastLine(stderr.String()); line != "" {
+			msg += ": " + line
+		}
+		return nil, errors.New(msg)
+	}
+	return stdout.Bytes(), err
+}
+
+func lastLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '\n'); i >= 0 {
+		s = s[i+1:]
+	}
+	return strings.TrimSpace(s)
+}
+
+// errorPayload repeats the last good data with props.error set, so the widget
+// keeps its shape while showing that the command is failing.
+func (m *ExecModule) errorPayload(err error) *protocol.DataPayload {
+	p := &protocol.DataPayload{}
+	if m.last != nil {
+		*p = *m.last
+	}
+	p.Props = maps.Clone(p.Props)
+	if p.Props == nil {
+		p.Props = make(map[string]any)
+	}
+	p.Props["error"] = err.Error()
+	return p
+}
+
+func (m *ExecModule) parse(out []byte) (*protocol.DataPayload, error) {
+	switch m.cfg.Parser {
+	case execParseRegex:
+		return m.parseRegex(out)
+	case execParseJSON:
+		return m.mapping.Payload(out, m.typ)
+	case execParseLines:
+		return m.parseLines(out)
+	}
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return nil, errors.New("no output")
+	}
+	v, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("not a number: %q", fields[0])
+	}
+	return &protocol.DataPayload{Value: v}, nil
+}
+
+// parseRegex maps the named groups "value", "label" and "displayValue" to
+// the payload fields of the same name; any other named group must be numeric
+// and becomes a series.
+func (m *ExecModule) parseRegex(out []byte) (*protocol.DataPayload, error) {
+	match := m.re.FindSubmatch(out)
+	if match == nil {
+		return nil, errors.New("output does not match pattern")
+	}
+	p := &protocol.DataPayload{}
+	for i, name := range m.re.SubexpNames() {
+		if name == "" {
+			continue
+		}
+		s := string(match[i])
+		switch name {
+		case "value":
+			if v, err := strconv.ParseFloat(s, 64); err == nil {
+				p.Value = v
+			} else {
+				p.Value = s
+			}
+		case "label":
+			p.Label = s
+		case "displayValue":
+			p.DisplayValue = s
+		default:
+			v, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("group %s: not a number: %q", name, s)
+			}
+			if p.Series == nil {
+				p.Series = make(map[string]float64)
+			}
+			p.Series[name] = v
+		}
+	}
+	return p, nil
+}
+
+// parseLines reads one "label value" row per line: the last field is the
+// value, the rest the label. Numeric values also become series. For a
+// bar-list, the bar is the value against props.max (default 100).
+func (m *ExecModule) parseLines(out []byte) (*protocol.DataPayload, error) {
+	scale := 100.0
+	if v, ok := m.cfg.Props["max"].(float64); ok && v > 0 {
+		scale = v
+	}
+
+	var bars []protocol.BarListItem
+	var kvs []protocol.KeyValueItem
+	series := make(map[string]float64)
+	seen := make(map[string]int)
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		label, value := fields[0], ""
+		if len(fields) > 1 {
+			label, value = strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+		}
+		// Row labels are React keys; keep them unique
+		if seen[label]++; seen[label] > 1 {
+			label = fmt.Sprintf("%s #%d", label, seen[label])
+		}
+
+		n, err := strconv.ParseFloat(value, 64)
+		isNum := err == nil
+		if isNum {
+			series[label] = n
+		}
+		bar := protocol.BarListItem{Label: label, Value: value}
+		if isNum {
+			bar.Percent = clampPercent(n / scale * 100)
+		}
+		bars = append(bars, bar)
+		kvs = append(kvs, protocol.KeyValueItem{Key: label, Value: value})
+	}
+	if len(bars) == 0 {
+		return nil, errors.New("no output")
+	}
+
+	p := &protocol.DataPayload{Value: float64(len(bars)), Series: series}
+	if m.typ == protocol.TypeKeyValue {
+		p.Items = kvs
+	} else {
+		p.Items = bars
+	}
+	return p, nil
+}
+
+func clampPercent(v float64) float64 {
+	return round(min(100, max(0, v)), 1)
+}
@@ -0,0 +1,179 @@
+package modules
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"glancehud/internal/protocol"
+)
+
+func newTestExec(t *testing.T, cfg ExecConfig) *ExecModule {
+	t.Helper()
+	if cfg.Name == "" {
+		cfg.Name = "t"
+	}
+	m, err := NewExecModule(cfg)
+	if err != nil {
+		t.Fatalf("NewExecModule: %v", err)
+	}
+	return m
+}
+
+func TestNewExecModule_Validation(t *testing.T) {
+	bad := []ExecConfig{
+		{Name: "", Command: []string{"true"}},
+		{Name: "a b", Command: []string{"true"}},
+		{Name: "x"},
+		{Name: "x", Command: []string{"true"}, Type: "group"},
+		{Name: "x", Command: []string{"true"}, Interval: "100ms"},
+		{Name: "x", Command: []string{"true"}, Parser: "xml"},
+		{Name: "x", Command: []string{"true"}, Parser: "regex", Pattern: `(\d+)`},
+		{Name: "x", Command: []string{"true"}, Parser: "json", Fields: map[string]string{"colour": "c"}},
+		{Name: "x", Command: []string{"true"}, Parser: "json", Fields: map[string]string{"value": "a[x]"}},
+	}
+	for _, cfg := range bad {
+		if _, err := NewExecModule(cfg); err == nil {
+			t.Errorf("want error for %+v", cfg)
+		}
+	}
+
+	m := newTestExec(t, ExecConfig{Name: "queue", Command: []string{"true"}, Interval: "30s", Type: "key-value"})
+	if m.ID() != "exec.queue" || m.Interval() != 30*time.Second {
+		t.Errorf("got id=%s interval=%s", m.ID(), m.Interval())
+	}
+	rc := m.GetRenderConfig()
+	if rc.ID != "glancehud.exec.queue" || rc.Title != "queue" || rc.Props["layout"] != "column" {
+		t.Errorf("render config: %+v", rc)
+	}
+}
+
+func TestExecModule_ParseNumber(t *testing.T) {
+	m := newTestExec(t, ExecConfig{Command: []string{"x"}})
+	p, err := m.parse([]byte("  42.5 jobs\n"))
+	if err != nil || p.Value != 42.5 {
+		t.Errorf("got %+v, %v", p, err)
+	}
+	if _, err := m.parse([]byte("n/a")); err == nil {
+		t.Error("want error for non-numeric output")
+	}
+}
+
+func TestExecModule_ParseRegex(t *testing.T) {
+	m := newTestExec(t, ExecConfig{
+		Command: []string{"x"},
+		Parser:  "regex",
+		Pattern: `queued: (?P<value>\d+), running: (?P<running>\d+) \((?P<label>\w+)\)`,
+	})
+	p, err := m.parse([]byte("queued: 7, running: 3 (ok)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Value != 7.0 || p.Label != "ok" || p.Series["running"] != 3 {
+		t.Errorf("got %+v", p)
+	}
+	if _, err := m.parse([]byte("idle")); err == nil {
+		t.Error("want error when pattern does not match")
+	}
+}
+
+func TestExecModule_ParseJSON(t *testing.T) {
+	out := []byte(`{"queue":{"depth":12,"state":"draining"},"workers":[{"busy":3}],"shards":{"b":80,"a":20}}`)
+
+	m := newTestExec(t, ExecConfig{
+		Command: []string{"x"},
+		Type:    "bar-list",
+		Parser:  "json",
+		Fields: map[string]string{
+			"value":        "queue.depth",
+			"label":        "queue.state",
+			"series.busy":  "workers[0].busy",
+			"items":        "shards",
+			"displayValue": "queue.state",
+		},
+	})
+	p, err := m.parse(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Value != 12.0 || p.Label != "draining" || p.Series["busy"] != 3 {
+		t.Errorf("got %+v", p)
+	}
+	items := p.Items.([]protocol.BarListItem)
+	if len(items) != 2 || items[0].Label != "a" || items[0].Percent != 20 || items[1].Value != "80" {
+		t.Errorf("items: %+v", items)
+	}
+
+	missing := newTestExec(t, ExecConfig{Command: []string{"x"}, Parser: "json", Fields: map[string]string{"value": "queue.size"}})
+	if _, err := missing.parse(out); err == nil || !strings.Contains(err.Error(), "queue.size") {
+		t.Errorf("want path-not-found error, got %v", err)
+	}
+
+	// Without fields, stdout is a DataPayload
+	raw := newTestExec(t, ExecConfig{Command: []string{"x"}, Parser: "json"})
+	p, err = raw.parse([]byte(`{"value": 5, "displayValue": "5 builds"}`))
+	if err != nil || p.Value != 5.0 || p.DisplayValue != "5 builds" {
+		t.Errorf("got %+v, %v", p, err)
+	}
+}
+
+func TestExecModule_ParseLines(t *testing.T) {
+	m := newTestExec(t, ExecConfig{Command: []string{"x"}, Type: "bar-list", Parser: "lines", Props: map[string]any{"max": 50.0}})
+	p, err := m.parse([]byte("main queue 10\n\nretry 60\nretry 5\ndead n/a\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := p.Items.([]protocol.BarListItem)
+	want := []protocol.BarListItem{
+		{Label: "main queue", Percent: 20, Value: "10"},
+		{Label: "retry", Percent: 100, Value: "60"},
+		{Label: "retry #2", Percent: 10, Value: "5"},
+		{Label: "dead", Value: "n/a"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("want %d items, got %+v", len(want), items)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("[%d] want %+v, got %+v", i, want[i], items[i])
+		}
+	}
+	if p.Value != 4.0 || p.Series["main queue"] != 10 || len(p.Series) != 3 {
+		t.Errorf("got value=%v series=%v", p.Value, p.Series)
+	}
+}
+
+func TestExecModule_Update(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	m := newTestExec(t, ExecConfig{Command: []string{"sh", "-c", "echo 3"}})
+	p, _ := m.Update()
+	if p.Value != 3.0 || p.Props != nil {
+		t.Fatalf("got %+v", p)
+	}
+
+	// Non-zero exit keeps the last value and surfaces stderr
+	m.cfg.Command = []string{"sh", "-c", "echo 9; echo 'queue unreachable' >&2; exit 2"}
+	p, _ = m.Update()
+	if p.Value != 3.0 || p.Props["error"] != "exit status 2: queue unreachable" {
+		t.Errorf("got %+v", p)
+	}
+
+	m.cfg.Command = []string{"sh", "-c", "sleep 5"}
+	m.timeout = 50 * time.Millisecond
+	start := time.Now()
+	p, _ = m.Update()
+	if p.Props["error"] != "timed out after 50ms" {
+		t.Errorf("got %+v", p)
+	}
+	if time.Since(start) > 3*time.Second {
+		t.Errorf("timeout not enforced: took %s", time.Since(start))
+	}
+
+	m.cfg.Command = []string{"glancehud-no-such-command"}
+	if p, _ = m.Update(); p.Props["error"] == nil {
+		t.Error("want error for missing command")
+	}
+}
@@ -0,0 +1,398 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"glancehud/internal/protocol"
+	"io"
+	stdnet "net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Probe kinds.
+const (
+	probeHTTP = "http" // GET, check status and optional body regex
+	probeTCP  = "tcp"  // connect only
+	probeDNS  = "dns"  // resolve a host name
+)
+
+const (
+	defaultProbeTimeout = 5 * time.Second
+	maxProbeBody        = 64 << 10 // body bytes read for the regex check
+)
+
+// HealthModule runs HTTP, TCP and DNS probes against local services and
+// reports each one's status and latency.
+type HealthModule struct {
+	probes    []probe
+	interval  time.Duration
+	failAfter int    // consecutive errors before a probe counts as failed
+	display   string // "list" | "sparkline"
+
+	state map[string]*probeState // by probe label
+}
+
+// probe is one parsed entry of the "probes" prop.
+type probe struct {
+	label   string
+	kind    string
+	target  string // URL, host:port or host name
+	timeout time.Duration
+	status  int            // http: expected status; 0 means any 2xx/3xx
+	body    *regexp.Regexp // http: optional body match
+	server  string         // dns: optional resolver host:port
+}
+
+// probeState is the outcome history of one probe across Update calls.
+type probeState struct {
+	failures int // consecutive
+	latency  time.Duration
+	err      error
+}
+
+func NewHealthModule() *HealthModule {
+	return &HealthModule{
+		interval:  30 * time.Second,
+		failAfter: 3,
+		display:   "list",
+		state:     make(map[string]*probeState),
+	}
+}
+
+func (m *HealthModule) ID() string {
+	return "health"
+}
+
+func (m *HealthModule) Interval() time.Duration {
+	return m.interval
+}
+
+func (m *HealthModule) ApplyConfig(props map[string]interface{}) {
+	if val, ok := props["probes"].(string); ok {
+		m.probes = parseProbes(val)
+	}
+	if val, ok := props["interval"].(float64); ok && val >= 1 {
+		m.interval = time.Duration(val) * time.Second
+	}
+	if val, ok := props["fail_after"].(float64); ok && val >= 1 {
+		m.failAfter = int(val)
+	}
+	if val, ok := props["display"].(string); ok && (val == "list" || val == "sparkline") {
+		m.display = val
+	}
+}
+
+// parseProbes parses semicolon-separated probes of the form
+// "[label=]kind:target [option=value ...]", e.g.
+//
+//	api=http:http://localhost:8080/health status=200 body=ok timeout=2s;
+//	db=tcp:localhost:5432; dns=dns:git.internal server=10.0.0.53:53
+//
+// Options are whitespace-separated, so a body regex uses \s for spaces.
+// Entries with an unknown kind or invalid option are skipped.
+func parseProbes(s string) []probe {
+	var out []probe
+	for _, entry := range strings.Split(s, ";") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+		spec := fields[0]
+		label := ""
+		if l, rest, ok := strings.Cut(spec, "="); ok && !strings.ContainsAny(l, ":/") {
+			label, spec = l, rest
+		}
+		kind, target, ok := strings.Cut(spec, ":")
+		if !ok || target == "" || (kind != probeHTTP && kind != probeTCP && kind != probeDNS) {
+			continue
+		}
+		p := probe{label: label, kind: kind, target: target, timeout: defaultProbeTimeout}
+		if p.label == "" {
+			p.label = target
+		}
+		if applyProbeOptions(&p, fields[1:]) != nil {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
+
+func applyProbeOptions(p *probe, opts []string) error {
+	for _, opt := range opts {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			return fmt.Errorf("probe %s: option %q is not key=value", p.label, opt)
+		}
+		var err error
+		switch k {
+		case "timeout":
+			p.timeout, err = time.ParseDuration(v)
+		case "status":
+			p.status, err = strconv.Atoi(v)
+		case "body":
+			p.body, err = regexp.Compile(v)
+		case "server":
+			p.server = v
+		default:
+			err = fmt.Errorf("unknown option %q", k)
+		}
+		if err != nil {
+			return fmt.Errorf("probe %s: %w", p.label, err)
+		}
+	}
+	return nil
+}
+
+func (m *HealthModule) GetConfigSchema() []protocol.ConfigSchema {
+	return []protocol.ConfigSchema{
+		{
+			Name:  "probes",
+			Label: "Probes (label=http:URL status=200 body=re; label=tcp:host:port; label=dns:name)",
+			Type:  protocol.ConfigText,
+		},
+		{
+			Name:    "interval",
+			Label:   "Interval (s)",
+			Type:    protocol.ConfigNumber,
+			Default: 30,
+		},
+		{
+			Name:    "fail_after",
+			Label:   "Fail After N Errors",
+			Type:    protocol.ConfigNumber,
+			Default: 3,
+		},
+		{
+			Name:    "display",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: "list",
+			Options: []protocol.SelectOption{
+				{Label: "Status list", Value: "list"},
+				{Label: "Latency sparkline", Value: "sparkline"},
+			},
+		},
+	}
+}
+
+func (m *HealthModule) GetRenderConfig() protocol.RenderConfig {
+	if m.display == "sparkline" {
+		series := make([]map[string]any, 0, len(m.probes))
+		for i, p := range m.probes {
+			series = append(series, map[string]any{
+				"key":   p.label,
+				"label": p.label,
+				"color": SeriesPalette[i%len(SeriesPalette)],
+			})
+		}
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.health",
+			Type:  protocol.TypeSpark,
+			Title: "Health",
+			Props: map[string]any{
+				"unit":      " ms",
+				"maxPoints": 60,
+				"series":    series,
+			},
+		}
+	}
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.health",
+		Type:  protocol.TypeKeyValue,
+		Title: "Health",
+		Props: map[string]any{
+			"layout": "column",
+		},
+	}
+}
+
+func (m *HealthModule) Update() (*protocol.DataPayload, error) {
+	results := runProbes(context.Background(), m.probes)
+	return m.record(results), nil
+}
+
+// probeResult is the outcome of one probe run.
+type probeResult struct {
+	latency time.Duration
+	err     error
+}
+
+// runProbes runs every probe concurrently, each bounded by its own timeout,
+// so one hung endpoint cannot delay the others.
+func runProbes(ctx context.Context, probes []probe) []probeResult {
+	results := make([]probeResult, len(probes))
+	var wg sync.WaitGroup
+	for i, p := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pctx, cancel := context.WithTimeout(ctx, p.timeout)
+			defer cancel()
+			start := time.Now()
+			err := p.run(pctx)
+			results[i] = probeResult{latency: time.Since(start), err: err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (p probe) run(ctx context.Context) error {
+	switch p.kind {
+	case probeHTTP:
+		return p.runHTTP(ctx)
+	case probeTCP:
+		var d stdnet.Dialer
+		conn, err := d.DialContext(ctx, "tcp", p.target)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case probeDNS:
+		r := stdnet.DefaultResolver
+		if p.server != "" {
+			r = &stdnet.Resolver{
+				PreferGo: true,
+				Dial: func(ctx context.Context, network, _ string) (stdnet.Conn, error) {
+					var d stdnet.Dialer
+					return d.DialContext(ctx, network, p.server)
+				},
+			}
+		}
+		addrs, err := r.LookupHost(ctx, p.target)
+		if err != nil {
+			return err
+		}
+		if len(addrs) == 0 {
+			return errors.New("no addresses")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown probe kind %q", p.kind)
+}
+
+func (p probe) runHTTP(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.target, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if p.status != 0 && resp.StatusCode != p.status {
+		return fmt.Errorf("status %d, want %d", resp.StatusCode, p.status)
+	}
+	if p.status == 0 && resp.StatusCode >= 400 {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	if p.body != nil {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxProbeBody))
+		if err != nil {
+			return err
+		}
+		if !p.body.Match(body) {
+			return fmt.Errorf("body does not match %q", p.body)
+		}
+	}
+	return nil
+}
+
+// shortProbeError trims errors to what fits on one HUD row: the URL is
+// already the probe's label, and deadline errors just mean "timeout".
+func shortProbeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return errors.New("timeout")
+	}
+	var ue *url.Error
+	if errors.As(err, &ue) {
+		return shortProbeError(ue.Err)
+	}
+	var oe *stdnet.OpError
+	if errors.As(err, &oe) && oe.Err != nil {
+		return oe.Err
+	}
+	return err
+}
+
+// record folds a round of results into the per-probe state and builds the
+// payload. A probe is only shown as failed after failAfter consecutive
+// errors; before that it is "flaky" and keeps its last good latency in the
+// row. Failed rounds add no series point, so the sparkline never plots a
+// stale or zero latency for a probe that is not answering.
+func (m *HealthModule) record(results []probeResult) *protocol.DataPayload {
+	next := make(map[string]*probeState, len(m.probes))
+	items := make([]protocol.KeyValueItem, 0, len(m.probes))
+	series := make(map[string]float64, len(m.probes))
+	var failed []string
+	var worst float64
+
+	for i, p := range m.probes {
+		st, ok := m.state[p.label]
+		if !ok {
+			st = &probeState{}
+		}
+		next[p.label] = st
+
+		r := results[i]
+		r.err = shortProbeError(r.err)
+		st.err = r.err
+		if r.err == nil {
+			st.failures = 0
+			st.latency = r.latency
+		} else {
+			st.failures++
+		}
+
+		ms := round(float64(st.latency.Microseconds())/1000, 1)
+		if r.err == nil {
+			series[p.label] = ms
+			worst = max(worst, ms)
+		}
+
+		switch {
+		case st.failures == 0:
+			items = append(items, protocol.KeyValueItem{Key: p.label, Value: fmt.Sprintf("%.0f ms", ms), Icon: "CheckCircle"})
+		case st.failures < m.failAfter:
+			items = append(items, protocol.KeyValueItem{
+				Key:   p.label,
+				Value: fmt.Sprintf("flaky (%d/%d) · %s", st.failures, m.failAfter, r.err),
+				Icon:  "AlertCircle",
+			})
+		default:
+			failed = append(failed, p.label)
+			items = append(items, protocol.KeyValueItem{Key: p.label, Value: "down · " + r.err.Error(), Icon: "XCircle"})
+		}
+	}
+	m.state = next
+
+	if len(items) == 0 {
+		items = []protocol.KeyValueItem{{Key: "Health", Value: "no probes configured", Icon: "AlertCircle"}}
+	}
+	payload := &protocol.DataPayload{
+		Value:        worst,
+		Items:        items,
+		Series:       series,
+		DisplayValue: fmt.Sprintf("%d/%d up", len(m.probes)-len(failed), len(m.probes)),
+	}
+	if len(failed) > 0 {
+		payload.Props = map[string]any{
+			"color":  "#ef4444",
+			"failed": failed,
+		}
+	}
+	return payload
+}
@@ -0,0 +1,165 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"glancehud/internal/protocol"
+)
+
+func TestParseProbes(t *testing.T) {
+	got := parseProbes(`api=http:http://localhost:8080/health status=200 body=ok\s+up timeout=2s;
+		tcp:localhost:5432; dns=dns:example.com server=10.0.0.53:53; bad=ftp:x; x=tcp:y:1 bogus=1; y=http:http://h timeout=soon`)
+	if len(got) != 3 {
+		t.Fatalf("want 3 probes, got %+v", got)
+	}
+	api := got[0]
+	if api.label != "api" || api.kind != probeHTTP || api.target != "http://localhost:8080/health" ||
+		api.status != 200 || api.timeout != 2*time.Second || !api.body.MatchString("ok  up") {
+		t.Errorf("api: got %+v", api)
+	}
+	if got[1].label != "localhost:5432" || got[1].kind != probeTCP || got[1].timeout != defaultProbeTimeout {
+		t.Errorf("unlabelled tcp: got %+v", got[1])
+	}
+	if got[2].kind != probeDNS || got[2].target != "example.com" || got[2].server != "10.0.0.53:53" {
+		t.Errorf("dns: got %+v", got[2])
+	}
+}
+
+func TestProbe_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			fmt.Fprint(w, `{"status":"up"}`)
+		case "/slow":
+			time.Sleep(200 * time.Millisecond)
+		default:
+			http.Error(w, "nope", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		spec    string
+		wantErr string
+	}{
+		{"http:" + srv.URL + "/ok", ""},
+		{"http:" + srv.URL + "/ok status=200 body=\"up\"", ""},
+		{"http:" + srv.URL + "/ok status=204", "status 200, want 204"},
+		{"http:" + srv.URL + "/ok body=down", `body does not match "down"`},
+		{"http:" + srv.URL + "/fail", "status 503"},
+		{"http:" + srv.URL + "/fail status=503", ""},
+		{"http:" + srv.URL + "/slow timeout=20ms", "timeout"},
+	}
+	for _, tt := range tests {
+		probes := parseProbes(tt.spec)
+		if len(probes) != 1 {
+			t.Fatalf("%s: parse failed", tt.spec)
+		}
+		r := runProbes(context.Background(), probes)[0]
+		err := shortProbeError(r.err)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.spec, err)
+		case tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr):
+			t.Errorf("%s: want error %q, got %v", tt.spec, tt.wantErr, err)
+		}
+	}
+}
+
+func TestProbe_TCPAndDNS(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			c.Close()
+		}
+	}()
+
+	// Grab a free port and close it so nothing listens there
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	probes := parseProbes("up=tcp:" + ln.Addr().String() + "; gone=tcp:" + closedAddr + " timeout=1s; dns=dns:localhost")
+	results := runProbes(context.Background(), probes)
+	if results[0].err != nil {
+		t.Errorf("tcp to listener: %v", results[0].err)
+	}
+	if results[1].err == nil {
+		t.Error("tcp to closed port should fail")
+	}
+	if results[2].err != nil {
+		t.Errorf("dns localhost: %v", results[2].err)
+	}
+}
+
+func TestHealthModule_Record(t *testing.T) {
+	m := NewHealthModule()
+	m.ApplyConfig(map[string]interface{}{"probes": "api=tcp:a:1; db=tcp:b:2", "fail_after": 2.0})
+	ok := probeResult{latency: 12 * time.Millisecond}
+	bad := probeResult{latency: time.Second, err: errors.New("connection refused")}
+
+	p := m.record([]probeResult{ok, ok})
+	if p.DisplayValue != "2/2 up" || p.Series["api"] != 12 || p.Props != nil {
+		t.Errorf("all up: got %+v", p)
+	}
+
+	// First error: flaky, last good latency kept
+	p = m.record([]probeResult{bad, ok})
+	items := p.Items.([]protocol.KeyValueItem)
+	if items[0].Icon != "AlertCircle" || items[0].Value != "flaky (1/2) · connection refused" {
+		t.Errorf("flaky: got %+v", items[0])
+	}
+	if _, ok := p.Series["api"]; ok || p.Series["db"] != 12 {
+		t.Errorf("failing probe must not report latency, got series=%v", p.Series)
+	}
+	if p.Props != nil {
+		t.Error("flaky probe should not flag failure")
+	}
+
+	// Second consecutive error: down
+	p = m.record([]probeResult{bad, ok})
+	items = p.Items.([]protocol.KeyValueItem)
+	if items[0].Icon != "XCircle" || p.DisplayValue != "1/2 up" {
+		t.Errorf("down: got %+v %q", items[0], p.DisplayValue)
+	}
+	if failed, _ := p.Props["failed"].([]string); len(failed) != 1 || failed[0] != "api" {
+		t.Errorf("want failed [api], got %v", p.Props)
+	}
+
+	if _, ok := p.Series["api"]; ok {
+		t.Errorf("down probe must not report latency, got series=%v", p.Series)
+	}
+
+	// Recovery resets the count
+	p = m.record([]probeResult{ok, ok})
+	if items := p.Items.([]protocol.KeyValueItem); items[0].Icon != "CheckCircle" || p.Props != nil {
+		t.Errorf("recovered: got %+v", items[0])
+	}
+}
+
+func TestHealthModule_Record_NeverSucceeded(t *testing.T) {
+	m := NewHealthModule()
+	m.ApplyConfig(map[string]interface{}{"probes": "api=tcp:a:1"})
+	p := m.record([]probeResult{{err: errors.New("connection refused")}})
+	if _, ok := p.Series["api"]; ok {
+		t.Errorf("probe that never succeeded must not plot 0 ms, got %v", p.Series)
+	}
+}
@@ -0,0 +1,125 @@
+package modules
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/shirou/gopsutil/v4/common"
+)
+
+// HostPaths points module reads at another root filesystem, typically the
+// host's /proc, /sys, /etc and / bind-mounted into a container. Empty fields
+// keep the default (or gopsutil's own HOST_* environment variable).
+type HostPaths struct {
+	Proc string `json:"proc,omitempty"` // e.g. "/host/proc"
+	Sys  string `json:"sys,omitempty"`  // e.g. "/host/sys"
+	Etc  string `json:"etc,omitempty"`  // e.g. "/host/etc"
+	Root string `json:"root,omitempty"` // e.g. "/host/root"; disk usage is read under it
+}
+
+// Merge returns p with every non-empty field of o applied on top.
+func (p HostPaths) Merge(o HostPaths) HostPaths {
+	if o.Proc != "" {
+		p.Proc = o.Proc
+	}
+	if o.Sys != "" {
+		p.Sys = o.Sys
+	}
+	if o.Etc != "" {
+		p.Etc = o.Etc
+	}
+	if o.Root != "" {
+		p.Root = o.Root
+	}
+	return p
+}
+
+// HostPathsFromEnv reads the same HOST_PROC, HOST_SYS, HOST_ETC and HOST_ROOT
+// variables gopsutil understands.
+func HostPathsFromEnv() HostPaths {
+	return HostPaths{
+		Proc: os.Getenv("HOST_PROC"),
+		Sys:  os.Getenv("HOST_SYS"),
+		Etc:  os.Getenv("HOST_ETC"),
+		Root: os.Getenv("HOST_ROOT"),
+	}
+}
+
+// hostState is swapped atomically so module goroutines never see a
+// half-applied set of paths.
+type hostState struct {
+	paths HostPaths
+	ctx   context.Context
+}
+
+var currentHost atomic.Pointer[hostState]
+
+func init() {
+	SetHostPaths(HostPaths{})
+}
+
+// SetHostPaths applies p to every module. gopsutil calls receive it through
+// hostContext; direct /proc and /sys reads go through hostProc and hostSys.
+func SetHostPaths(p HostPaths) {
+	env := common.EnvMap{}
+	if p.Proc != "" {
+		env[common.HostProcEnvKey] = p.Proc
+	}
+	if p.Sys != "" {
+		env[common.HostSysEnvKey] = p.Sys
+	}
+	if p.Etc != "" {
+		env[common.HostEtcEnvKey] = p.Etc
+	}
+	if p.Root != "" {
+		env[common.HostRootEnvKey] = p.Root
+	}
+	currentHost.Store(&hostState{
+		paths: p,
+		ctx:   context.WithValue(context.Background(), common.EnvKey, env),
+	})
+}
+
+// CurrentHostPaths returns the paths last passed to SetHostPaths.
+func CurrentHostPaths() HostPaths {
+	return currentHost.Load().paths
+}
+
+// hostContext carries the configured host paths to gopsutil. Every gopsutil
+// call in this package should use its WithContext variant with this context.
+func hostContext() context.Context {
+	return currentHost.Load().ctx
+}
+
+// hostSys, hostProc and hostRoot join path elements under the configured
+// host root, falling back to the HOST_* environment variable and then the
+// local default, in the same order gopsutil resolves them. hostRoot has no
+// default: without a root the path is returned as is, so Windows drive
+// letters are not re-rooted under "/".
+func hostSys(elem ...string) string {
+	return hostPath(currentHost.Load().paths.Sys, "HOST_SYS", "/sys", elem)
+}
+
+func hostProc(elem ...string) string {
+	return hostPath(currentHost.Load().paths.Proc, "HOST_PROC", "/proc", elem)
+}
+
+func hostRoot(elem ...string) string {
+	return hostPath(currentHost.Load().paths.Root, "HOST_ROOT", "", elem)
+}
+
+func hostPath(configured, env, fallback string, elem []string) string {
+	root := configured
+	if root == "" {
+		root = os.Getenv(env)
+	}
+	if root == "" {
+		root = fallback
+	}
+	if root == "" {
+		return filepath.Join(elem...)
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
@@ -0,0 +1,75 @@
+package modules
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/common"
+)
+
+func setHostPaths(t *testing.T, p HostPaths) {
+	t.Helper()
+	SetHostPaths(p)
+	t.Cleanup(func() { SetHostPaths(HostPaths{}) })
+}
+
+func TestHostPaths_Merge(t *testing.T) {
+	base := HostPaths{Proc: "/cfg/proc", Sys: "/cfg/sys"}
+	got := base.Merge(HostPaths{Sys: "/env/sys", Root: "/env/root"})
+	want := HostPaths{Proc: "/cfg/proc", Sys: "/env/sys", Root: "/env/root"}
+	if got != want {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+func TestHostPaths_Resolution(t *testing.T) {
+	t.Setenv("HOST_SYS", "/env/sys")
+	t.Setenv("HOST_PROC", "")
+	setHostPaths(t, HostPaths{Proc: "/host/proc", Root: "/host/root"})
+
+	if got := hostProc("1", "net", "dev"); got != filepath.FromSlash("/host/proc/1/net/dev") {
+		t.Errorf("configured proc: got %s", got)
+	}
+	if got := hostSys("class", "hwmon"); got != filepath.FromSlash("/env/sys/class/hwmon") {
+		t.Errorf("env fallback for sys: got %s", got)
+	}
+	if got := hostRoot("home"); got != filepath.FromSlash("/host/root/home") {
+		t.Errorf("configured root: got %s", got)
+	}
+
+	env, _ := hostContext().Value(common.EnvKey).(common.EnvMap)
+	if env[common.HostProcEnvKey] != "/host/proc" || env[common.HostRootEnvKey] != "/host/root" {
+		t.Errorf("gopsutil context missing overrides: %v", env)
+	}
+	if _, ok := env[common.HostSysEnvKey]; ok {
+		t.Error("unset paths must not shadow the HOST_SYS env var")
+	}
+}
+
+func TestHostPaths_NoRootLeavesPathUnchanged(t *testing.T) {
+	t.Setenv("HOST_ROOT", "")
+	setHostPaths(t, HostPaths{})
+
+	abs := filepath.FromSlash("/var/run/app.pid")
+	if vol := filepath.VolumeName(t.TempDir()); vol != "" {
+		abs = vol + `\` // e.g. C:\ on Windows
+	}
+	if got := hostRoot(abs); got != abs {
+		t.Errorf("want %s unchanged, got %s", abs, got)
+	}
+}
+
+func TestHostPaths_AppliedToModules(t *testing.T) {
+	root := t.TempDir()
+	writeHwmon(t, root, "hwmon0", map[string]string{
+		"name":        "k10temp",
+		"temp1_input": "42000",
+	})
+	t.Setenv("HOST_SYS", "")
+	setHostPaths(t, HostPaths{Sys: root})
+
+	got := readSensors()
+	if len(got) != 1 || got[0].key != "k10temp" || got[0].value != 42 {
+		t.Errorf("sensors should read the configured sys root, got %+v", got)
+	}
+}
@@ -0,0 +1,198 @@
+package modules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"glancehud/internal/jsonpath"
+	"glancehud/internal/protocol"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// JSONMapping maps paths in a JSON document onto DataPayload fields. It is
+// shared by exec widgets and pulled URLs.
+//
+// Keys are "value", "label", "displayValue", "items" and "series.<key>".
+// When "items" points at an array of objects, "items.<field>" paths pick each
+// row's label, key, percent, value, color and icon relative to the element.
+// An empty mapping means the document is a DataPayload already.
+type JSONMapping struct {
+	fields map[string]jsonpath.Path
+	item   map[string]jsonpath.Path // "items.<field>", by field
+	paths  map[string]string        // as configured, for error messages
+}
+
+// itemFields are the row fields "items.<field>" may fill.
+var itemFields = []string{"label", "key", "percent", "value", "color", "icon"}
+
+// NewJSONMapping compiles a payload field → JSON path mapping.
+func NewJSONMapping(fields map[string]string) (*JSONMapping, error) {
+	jm := &JSONMapping{
+		fields: make(map[string]jsonpath.Path),
+		item:   make(map[string]jsonpath.Path),
+		paths:  fields,
+	}
+	for field, path := range fields {
+		segs, err := jsonpath.Parse(path)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field, err)
+		}
+		if sub, ok := strings.CutPrefix(field, "items."); ok {
+			if !slices.Contains(itemFields, sub) {
+				return nil, fmt.Errorf("unknown item field %q", field)
+			}
+			jm.item[sub] = segs
+			continue
+		}
+		if !validPayloadField(field) {
+			return nil, fmt.Errorf("unknown payload field %q", field)
+		}
+		jm.fields[field] = segs
+	}
+	if _, ok := jm.fields["items"]; len(jm.item) > 0 && !ok {
+		return nil, errors.New(`"items.<field>" requires "items"`)
+	}
+	return jm, nil
+}
+
+// validPayloadField reports whether field names a DataPayload field a mapping
+// can fill.
+func validPayloadField(field string) bool {
+	switch field {
+	case "value", "label", "displayValue", "items":
+		return true
+	}
+	key, ok := strings.CutPrefix(field, "series.")
+	return ok && key != ""
+}
+
+// Payload decodes data and applies the mapping. typ picks the row type for
+// mapped items: BarListItem, or KeyValueItem for key-value widgets.
+func (jm *JSONMapping) Payload(data []byte, typ protocol.ComponentType) (*protocol.DataPayload, error) {
+	if len(jm.fields) == 0 {
+		var p protocol.DataPayload
+		if err := json.Unmarshal(data, &p); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return &p, nil
+	}
+
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	p := &protocol.DataPayload{}
+	for field, segs := range jm.fields {
+		v, ok := segs.Lookup(doc)
+		if !ok {
+			return nil, fmt.Errorf("%s: path %q not found", field, jm.paths[field])
+		}
+		switch field {
+		case "value":
+			p.Value = v
+		case "label":
+			p.Label = jsonString(v)
+		case "displayValue":
+			p.DisplayValue = jsonString(v)
+		case "items":
+			items, err := jm.items(v, typ)
+			if err != nil {
+				return nil, fmt.Errorf("items: %w", err)
+			}
+			p.Items = items
+		default:
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fmt.Errorf("%s: not a number", field)
+			}
+			if p.Series == nil {
+				p.Series = make(map[string]float64)
+			}
+			p.Series[strings.TrimPrefix(field, "series.")] = f
+		}
+	}
+	return p, nil
+}
+
+// items converts the mapped "items" value. Objects become one row per key,
+// sorted. Arrays are mapped element by element through the "items.<field>"
+// paths, or passed through as already item-shaped when there are none.
+func (jm *JSONMapping) items(v any, typ protocol.ComponentType) (any, error) {
+	switch v := v.(type) {
+	case []any:
+		if len(jm.item) == 0 {
+			return v, nil
+		}
+		rows := make([]map[string]any, 0, len(v))
+		for _, el := range v {
+			row := make(map[string]any, len(jm.item))
+			for f, segs := range jm.item {
+				if fv, ok := segs.Lookup(el); ok {
+					row[f] = fv
+				}
+			}
+			rows = append(rows, row)
+		}
+		return itemRows(rows, typ), nil
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		rows := make([]map[string]any, 0, len(keys))
+		for _, k := range keys {
+			rows = append(rows, map[string]any{"label": k, "percent": v[k], "value": v[k]})
+		}
+		return itemRows(rows, typ), nil
+	}
+	return nil, errors.New("not an array or object")
+}
+
+// itemRows builds typed rows from generic ones. "label" and "key" are
+// interchangeable so one mapping works for both widget types.
+func itemRows(rows []map[string]any, typ protocol.ComponentType) any {
+	if typ == protocol.TypeKeyValue {
+		items := make([]protocol.KeyValueItem, 0, len(rows))
+		for _, r := range rows {
+			key := jsonString(r["key"])
+			if key == "" {
+				key = jsonString(r["label"])
+			}
+			items = append(items, protocol.KeyValueItem{Key: key, Value: jsonString(r["value"]), Icon: jsonString(r["icon"])})
+		}
+		return items
+	}
+	items := make([]protocol.BarListItem, 0, len(rows))
+	for _, r := range rows {
+		label := jsonString(r["label"])
+		if label == "" {
+			label = jsonString(r["key"])
+		}
+		pct, _ := r["percent"].(float64)
+		items = append(items, protocol.BarListItem{
+			Label:   label,
+			Percent: clampPercent(pct),
+			Value:   jsonString(r["value"]),
+			Color:   jsonString(r["color"]),
+		})
+	}
+	return items
+}
+
+// jsonString formats a decoded JSON scalar for display.
+func jsonString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -1,20 +1,56 @@
 package modules
 
 import (
+	"bufio"
 	"fmt"
 	"glancehud/internal/protocol"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/mem"
 )
 
+// Memory views. Each uses a different renderer, so a widget shows one at a time.
+const (
+	memViewGauge     = "gauge"     // used percent
+	memViewBreakdown = "breakdown" // bar-list: used/cached/buffers/available/swap
+	memViewPressure  = "pressure"  // key-value: Linux PSI avg10/avg60
+)
+
+var (
+	memBreakdownRows = []string{"used", "cached", "buffers", "available", "swap"}
+	psiResources     = []string{"cpu", "memory", "io"}
+)
+
 type MemModule struct {
 	minimalMode bool
+	view        string
+	rows        []string // breakdown rows to show
+	resources   []string // PSI resources to show
+}
+
+// psiLine is one "some" or "full" line of a /proc/pressure file.
+type psiLine struct {
+	avg10, avg60 float64
+}
+
+// psiStat is the parsed content of /proc/pressure/<resource>. Full is nil
+// when the kernel does not report it (cpu before 5.13).
+type psiStat struct {
+	some psiLine
+	full *psiLine
 }
 
 func NewMemModule() *MemModule {
 	return &MemModule{
 		minimalMode: false,
+		view:        memViewGauge,
+		rows:        memBreakdownRows,
+		resources:   psiResources,
 	}
 }
 
@@ -30,10 +66,71 @@ func (m *MemModule) ApplyConfig(props map[string]interface{}) {
 	if val, ok := props["minimal_mode"].(bool); ok {
 		m.minimalMode = val
 	}
+	if val, ok := props["view"].(string); ok {
+		switch val {
+		case memViewGauge, memViewBreakdown, memViewPressure:
+			m.view = val
+		}
+	}
+	if val, ok := props["rows"].([]interface{}); ok {
+		m.rows = pickKnown(val, memBreakdownRows)
+	}
+	if val, ok := props["psi"].([]interface{}); ok {
+		m.resources = pickKnown(val, psiResources)
+	}
+}
+
+// pickKnown keeps the known values from a checkbox list, in canonical
+// order. An empty selection falls back to all of them.
+func pickKnown(selected []interface{}, known []string) []string {
+	var out []string
+	for _, k := range known {
+		if slices.Contains(selected, interface{}(k)) {
+			out = append(out, k)
+		}
+	}
+	if len(out) == 0 {
+		return known
+	}
+	return out
 }
 
 func (m *MemModule) GetConfigSchema() []protocol.ConfigSchema {
-	return []protocol.ConfigSchema{}
+	rowOptions := make([]protocol.SelectOption, 0, len(memBreakdownRows))
+	for _, r := range memBreakdownRows {
+		rowOptions = append(rowOptions, protocol.SelectOption{Label: strings.ToUpper(r[:1]) + r[1:], Value: r})
+	}
+	return []protocol.ConfigSchema{
+		{
+			Name:    "view",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: memViewGauge,
+			Options: []protocol.SelectOption{
+				{Label: "Used % gauge", Value: memViewGauge},
+				{Label: "Breakdown & swap", Value: memViewBreakdown},
+				{Label: "Pressure stall (Linux PSI)", Value: memViewPressure},
+			},
+		},
+		{
+			Name:    "rows",
+			Label:   "Breakdown Rows",
+			Type:    protocol.ConfigCheckboxes,
+			Default: memBreakdownRows,
+			Options: rowOptions,
+		},
+		{
+			Name:    "psi",
+			Label:   "PSI Resources",
+			Type:    protocol.ConfigCheckboxes,
+			Default: psiResources,
+			Options: []protocol.SelectOption{
+				{Label: "CPU", Value: "cpu"},
+				{Label: "Memory", Value: "memory"},
+				{Label: "I/O", Value: "io"},
+			},
+		},
+	}
 }
 
 func (m *MemModule) GetRenderConfig() protocol.RenderConfig {
@@ -47,6 +144,23 @@ func (m *MemModule) GetRenderConfig() protocol.RenderConfig {
 			},
 		}
 	}
+	switch m.view {
+	case memViewBreakdown:
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.mem",
+			Type:  protocol.TypeBarList,
+			Title: "Memory",
+		}
+	case memViewPressure:
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.mem",
+			Type:  protocol.TypeKeyValue,
+			Title: "Pressure",
+			Props: map[string]any{
+				"layout": "column",
+			},
+		}
+	}
 	return protocol.RenderConfig{
 		ID:    "glancehud.core.mem",
 		Type:  protocol.TypeGauge,
@@ -60,7 +174,7 @@ func (m *MemModule) GetRenderConfig() protocol.RenderConfig {
 }
 
 func (m *MemModule) Update() (*protocol.DataPayload, error) {
-	vmStat, err := mem.VirtualMemory()
+	vmStat, err := mem.VirtualMemoryWithContext(hostContext())
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +195,19 @@ func (m *MemModule) Update() (*protocol.DataPayload, error) {
 		return payload, nil
 	}
 
+	switch m.view {
+	case memViewBreakdown:
+		var swap *mem.SwapMemoryStat
+		if slices.Contains(m.rows, "swap") {
+			swap, _ = mem.SwapMemoryWithContext(hostContext())
+		}
+		payload.Items, payload.Series = breakdownItems(vmStat, swap, m.rows)
+		return payload, nil
+	case memViewPressure:
+		payload.Items, payload.Series = m.pressureItems()
+		return payload, nil
+	}
+
 	payload.Items = map[string]any{
 		"used":  fmt.Sprintf("%.1f GB", usedGB),
 		"total": fmt.Sprintf("%.0f GB", totalGB),
@@ -88,3 +215,154 @@ func (m *MemModule) Update() (*protocol.DataPayload, error) {
 
 	return payload, nil
 }
+
+// breakdownItems builds one bar per selected row, each as a share of total
+// RAM (swap as a share of total swap). Cached and buffers are Linux-only and
+// are skipped when the platform reports zero. Series carries the same
+// percentages so alert rules can target e.g. "series.swap".
+func breakdownItems(vm *mem.VirtualMemoryStat, swap *mem.SwapMemoryStat, rows []string) ([]protocol.BarListItem, map[string]float64) {
+	share := func(b uint64) float64 {
+		if vm.Total == 0 {
+			return 0
+		}
+		return round(float64(b)/float64(vm.Total)*100, 1)
+	}
+
+	items := make([]protocol.BarListItem, 0, len(rows))
+	series := make(map[string]float64, len(rows))
+	add := func(label, key string, b uint64) {
+		pct := share(b)
+		series[key] = pct
+		items = append(items, protocol.BarListItem{Label: label, Percent: pct, Value: formatBytes(b)})
+	}
+	for _, row := range rows {
+		switch row {
+		case "used":
+			add("Used", row, vm.Used)
+		case "cached":
+			if vm.Cached > 0 {
+				add("Cached", row, vm.Cached)
+			}
+		case "buffers":
+			if vm.Buffers > 0 {
+				add("Buffers", row, vm.Buffers)
+			}
+		case "available":
+			add("Available", row, vm.Available)
+		case "swap":
+			if swap == nil {
+				continue
+			}
+			if swap.Total == 0 {
+				items = append(items, protocol.BarListItem{Label: "Swap", Value: "none"})
+				continue
+			}
+			pct := round(swap.UsedPercent, 1)
+			series[row] = pct
+			items = append(items, protocol.BarListItem{
+				Label:   "Swap",
+				Percent: pct,
+				Value:   formatBytes(swap.Used) + " / " + formatBytes(swap.Total),
+			})
+		}
+	}
+	return items, series
+}
+
+// pressureItems reads /proc/pressure/{cpu,memory,io}. Kernels without PSI
+// (or non-Linux hosts) get a single explanatory row instead of an error.
+func (m *MemModule) pressureItems() ([]protocol.KeyValueItem, map[string]float64) {
+	var items []protocol.KeyValueItem
+	series := make(map[string]float64)
+	for _, res := range m.resources {
+		f, err := os.Open(hostProc("pressure", res))
+		if err != nil {
+			continue
+		}
+		st, err := parsePSI(f)
+		f.Close()
+		if err != nil {
+			continue
+		}
+
+		series[res+"_some_avg10"] = st.some.avg10
+		series[res+"_some_avg60"] = st.some.avg60
+		value := fmt.Sprintf("some %.2f / %.2f", st.some.avg10, st.some.avg60)
+		if st.full != nil {
+			series[res+"_full_avg10"] = st.full.avg10
+			series[res+"_full_avg60"] = st.full.avg60
+			value += fmt.Sprintf(" · full %.2f / %.2f", st.full.avg10, st.full.avg60)
+		}
+		items = append(items, protocol.KeyValueItem{Key: psiLabel(res), Value: value, Icon: psiIcon(res)})
+	}
+	if len(items) == 0 {
+		items = []protocol.KeyValueItem{{Key: "PSI", Value: "unavailable", Icon: "Activity"}}
+	}
+	return items, series
+}
+
+func psiLabel(res string) string {
+	switch res {
+	case "cpu":
+		return "CPU"
+	case "io":
+		return "I/O"
+	}
+	return "Memory"
+}
+
+func psiIcon(res string) string {
+	switch res {
+	case "cpu":
+		return "Cpu"
+	case "io":
+		return "HardDrive"
+	}
+	return "MemoryStick"
+}
+
+// parsePSI parses the pressure file format:
+//
+//	some avg10=0.12 avg60=0.05 avg300=0.01 total=12345
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePSI(r io.Reader) (psiStat, error) {
+	var st psiStat
+	seenSome := false
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var line psiLine
+		for _, f := range fields[1:] {
+			k, v, ok := strings.Cut(f, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return st, fmt.Errorf("psi %s: %w", k, err)
+			}
+			switch k {
+			case "avg10":
+				line.avg10 = n
+			case "avg60":
+				line.avg60 = n
+			}
+		}
+		switch fields[0] {
+		case "some":
+			st.some, seenSome = line, true
+		case "full":
+			st.full = &line
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return st, err
+	}
+	if !seenSome {
+		return st, fmt.Errorf("psi: missing \"some\" line")
+	}
+	return st, nil
+}
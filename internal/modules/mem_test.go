@@ -0,0 +1,104 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// --- PSI ---
+
+func TestParsePSI(t *testing.T) {
+	st, err := parsePSI(strings.NewReader(
+		"some avg10=1.25 avg60=0.50 avg300=0.10 total=12345\n" +
+			"full avg10=0.30 avg60=0.05 avg300=0.00 total=678\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.some.avg10 != 1.25 || st.some.avg60 != 0.5 {
+		t.Errorf("unexpected some line %+v", st.some)
+	}
+	if st.full == nil || st.full.avg10 != 0.3 || st.full.avg60 != 0.05 {
+		t.Errorf("unexpected full line %+v", st.full)
+	}
+
+	// Older kernels report only "some" for cpu
+	st, err = parsePSI(strings.NewReader("some avg10=0.00 avg60=0.00 avg300=0.00 total=0\n"))
+	if err != nil || st.full != nil {
+		t.Errorf("want some-only stat, got %+v, %v", st, err)
+	}
+
+	if _, err := parsePSI(strings.NewReader("")); err == nil {
+		t.Error("want error for empty file")
+	}
+}
+
+func TestMemModule_PressureItems(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "pressure")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	psi := "some avg10=2.00 avg60=1.00 avg300=0.00 total=1\nfull avg10=0.50 avg60=0.25 avg300=0.00 total=1\n"
+	if err := os.WriteFile(filepath.Join(dir, "memory"), []byte(psi), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOST_PROC", root)
+
+	m := NewMemModule()
+	m.ApplyConfig(map[string]interface{}{"psi": []interface{}{"memory", "io"}})
+	items, series := m.pressureItems()
+	if len(items) != 1 || items[0].Key != "Memory" {
+		t.Fatalf("want only Memory (io missing), got %+v", items)
+	}
+	if items[0].Value != "some 2.00 / 1.00 · full 0.50 / 0.25" {
+		t.Errorf("unexpected value %q", items[0].Value)
+	}
+	if series["memory_some_avg10"] != 2 || series["memory_full_avg60"] != 0.25 {
+		t.Errorf("unexpected series %v", series)
+	}
+
+	t.Setenv("HOST_PROC", t.TempDir())
+	items, _ = m.pressureItems()
+	if len(items) != 1 || items[0].Value != "unavailable" {
+		t.Errorf("want unavailable placeholder, got %+v", items)
+	}
+}
+
+// --- Breakdown ---
+
+func TestBreakdownItems(t *testing.T) {
+	vm := &mem.VirtualMemoryStat{Total: 1000, Used: 400, Available: 500, Cached: 250}
+	swap := &mem.SwapMemoryStat{Total: 200, Used: 50, UsedPercent: 25}
+
+	items, series := breakdownItems(vm, swap, memBreakdownRows)
+	var labels []string
+	for _, it := range items {
+		labels = append(labels, it.Label)
+	}
+	// Buffers is zero (non-Linux) and skipped
+	if strings.Join(labels, ",") != "Used,Cached,Available,Swap" {
+		t.Errorf("unexpected rows %v", labels)
+	}
+	if series["used"] != 40 || series["cached"] != 25 || series["available"] != 50 || series["swap"] != 25 {
+		t.Errorf("unexpected series %v", series)
+	}
+
+	items, _ = breakdownItems(vm, &mem.SwapMemoryStat{}, []string{"swap"})
+	if len(items) != 1 || items[0].Value != "none" {
+		t.Errorf("want swap none, got %+v", items)
+	}
+}
+
+func TestPickKnown(t *testing.T) {
+	got := pickKnown([]interface{}{"io", "bogus", "cpu"}, psiResources)
+	if strings.Join(got, ",") != "cpu,io" {
+		t.Errorf("want cpu,io, got %v", got)
+	}
+	if got := pickKnown(nil, psiResources); len(got) != 3 {
+		t.Errorf("empty selection should mean all, got %v", got)
+	}
+}
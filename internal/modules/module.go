@@ -13,3 +13,10 @@ type Module interface {
 	ApplyConfig(props map[string]interface{})
 	Interval() time.Duration
 }
+
+// ActionHandler is optionally implemented by modules whose ConfigSchema
+// contains ConfigButton fields. HandleAction receives the button's Action.
+// It may run concurrently with Update, so implementations guard shared state.
+type ActionHandler interface {
+	HandleAction(action string) error
+}
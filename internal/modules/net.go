@@ -3,19 +3,48 @@ package modules
 import (
 	"fmt"
 	"glancehud/internal/protocol"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/shirou/gopsutil/v4/net"
 )
 
+// virtualIfacePrefixes are left unchecked by default: container bridges,
+// VPN tunnels and loopback usually just duplicate physical traffic. Loopback
+// is matched by isVirtualIface itself, since a "lo" prefix would also catch
+// Windows' "Local Area Connection" adapters.
+var virtualIfacePrefixes = []string{
+	"docker", "veth", "br-", "virbr", "vmnet", "vboxnet", "tun", "tap", "wg", "utun", "zt",
+}
+
 type NetModule struct {
-	prevNetIn  uint64
-	prevNetOut uint64
-	prevTime   time.Time
+	selectedIfaces []string // Empty means auto-detect physical interfaces
+	unit           string   // "bytes" | "bits"
+	display        string   // "list" | "sparkline"
+
+	prev map[string]ifaceSample
+}
+
+// ifaceSample is the last seen counter reading for one interface.
+type ifaceSample struct {
+	recv, sent uint64
+	at         time.Time
+}
+
+// ifaceRate is the computed throughput of one interface, in bytes/s.
+type ifaceRate struct {
+	name     string
+	down, up float64
 }
 
 func NewNetModule() *NetModule {
-	return &NetModule{}
+	return &NetModule{
+		unit:    "bytes",
+		display: "list",
+		prev:    make(map[string]ifaceSample),
+	}
 }
 
 func (m *NetModule) ID() string {
@@ -27,14 +56,119 @@ func (m *NetModule) Interval() time.Duration {
 }
 
 func (m *NetModule) ApplyConfig(props map[string]interface{}) {
-	// No config
+	// "interfaces" is a []interface{} from JSON deserialization
+	if val, ok := props["interfaces"].([]interface{}); ok {
+		m.selectedIfaces = nil
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				m.selectedIfaces = append(m.selectedIfaces, s)
+			}
+		}
+	}
+	if val, ok := props["unit"].(string); ok && (val == "bytes" || val == "bits") {
+		m.unit = val
+	}
+	if val, ok := props["display"].(string); ok && (val == "list" || val == "sparkline") {
+		m.display = val
+	}
 }
 
 func (m *NetModule) GetConfigSchema() []protocol.ConfigSchema {
-	return []protocol.ConfigSchema{}
+	options := discoverInterfaces()
+
+	// Default: physical interfaces checked
+	defaults := make([]string, 0, len(options))
+	for _, o := range options {
+		if !isVirtualIface(o.Value) {
+			defaults = append(defaults, o.Value)
+		}
+	}
+
+	return []protocol.ConfigSchema{
+		{
+			Name:    "interfaces",
+			Label:   "顯示網路介面",
+			Type:    protocol.ConfigCheckboxes,
+			Default: defaults,
+			Options: options,
+		},
+		{
+			Name:    "unit",
+			Label:   "Unit",
+			Type:    protocol.ConfigSelect,
+			Default: "bytes",
+			Options: []protocol.SelectOption{
+				{Label: "Bytes (B/s → GB/s)", Value: "bytes"},
+				{Label: "Bits (b/s → Gb/s)", Value: "bits"},
+			},
+		},
+		{
+			Name:    "display",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: "list",
+			Options: []protocol.SelectOption{
+				{Label: "Per-interface list", Value: "list"},
+				{Label: "Sparkline (up / down)", Value: "sparkline"},
+			},
+		},
+	}
+}
+
+func discoverInterfaces() []protocol.SelectOption {
+	var options []protocol.SelectOption
+	counters, err := readNetCounters()
+	if err != nil {
+		return options
+	}
+	for _, c := range counters {
+		options = append(options, protocol.SelectOption{
+			Label: c.Name,
+			Value: c.Name,
+		})
+	}
+	return options
+}
+
+// readNetCounters reads per-interface counters. /proc/net is a link into the
+// reading process's own network namespace, so with a host /proc mounted into
+// a container the host's interfaces are read through PID 1 instead.
+func readNetCounters() ([]net.IOCountersStat, error) {
+	ctx := hostContext()
+	if CurrentHostPaths().Proc != "" || os.Getenv("HOST_PROC") != "" {
+		return net.IOCountersByFileWithContext(ctx, true, hostProc("1", "net", "dev"))
+	}
+	return net.IOCountersWithContext(ctx, true)
+}
+
+func isVirtualIface(name string) bool {
+	lower := strings.ToLower(name)
+	if digits, ok := strings.CutPrefix(lower, "lo"); ok && strings.Trim(digits, "0123456789") == "" {
+		return true // lo, lo0
+	}
+	for _, p := range virtualIfacePrefixes {
+		if strings.HasPrefix(lower, p) {
+			return true
+		}
+	}
+	return strings.Contains(lower, "loopback")
 }
 
 func (m *NetModule) GetRenderConfig() protocol.RenderConfig {
+	if m.display == "sparkline" {
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.net",
+			Type:  protocol.TypeSpark,
+			Title: "Network",
+			Props: map[string]any{
+				"maxPoints": 60,
+				"series": []map[string]any{
+					{"key": "down", "label": "↓", "color": "#22c55e"},
+					{"key": "up", "label": "↑", "color": "#3b82f6"},
+				},
+			},
+		}
+	}
 	return protocol.RenderConfig{
 		ID:    "glancehud.core.net",
 		Type:  protocol.TypeKeyValue,
@@ -46,45 +180,96 @@ func (m *NetModule) GetRenderConfig() protocol.RenderConfig {
 }
 
 func (m *NetModule) Update() (*protocol.DataPayload, error) {
-	netCounters, err := net.IOCounters(false)
-	var netUp, netDown float64
-	var totalIn, totalOut uint64
-
-	if err == nil && len(netCounters) > 0 {
-		now := time.Now()
-		totalIn = netCounters[0].BytesRecv
-		totalOut = netCounters[0].BytesSent
-
-		if !m.prevTime.IsZero() {
-			elapsed := now.Sub(m.prevTime).Seconds()
-			if elapsed > 0 {
-				netDown = float64(totalIn-m.prevNetIn) / elapsed / 1024
-				netUp = float64(totalOut-m.prevNetOut) / elapsed / 1024
-			}
-		}
-		m.prevNetIn = totalIn
-		m.prevNetOut = totalOut
-		m.prevTime = now
+	counters, err := readNetCounters()
+	if err != nil {
+		return nil, err
 	}
+	rates := m.rates(counters, time.Now())
 
-	items := []protocol.KeyValueItem{
-		{
-			Key:   "UP",
-			Value: fmt.Sprintf("%.1f KB/s", netUp),
-			Icon:  "ArrowUp",
-		},
-		{
-			Key:   "DOWN",
-			Value: fmt.Sprintf("%.1f KB/s", netDown),
-			Icon:  "ArrowDown",
-		},
+	if m.display == "sparkline" {
+		var down, up float64
+		for _, r := range rates {
+			down += r.down
+			up += r.up
+		}
+		down, up = m.scale(down), m.scale(up)
+		return &protocol.DataPayload{
+			Value:        round(down, 1),
+			DisplayValue: fmt.Sprintf("↓ %s ↑ %s", m.formatRate(down), m.formatRate(up)),
+			Series: map[string]float64{
+				"down": round(down, 1),
+				"up":   round(up, 1),
+			},
+		}, nil
 	}
 
-	// Minimal mode logic? Net module is key-value by default, so it's already "minimal" enough.
-	// But maybe we want just "1.2K / 4.5K" text?
-	// For now, key-value is fine.
-
+	items := make([]protocol.KeyValueItem, 0, len(rates))
+	for _, r := range rates {
+		items = append(items, protocol.KeyValueItem{
+			Key:   r.name,
+			Value: fmt.Sprintf("↓ %s ↑ %s", m.formatRate(m.scale(r.down)), m.formatRate(m.scale(r.up))),
+			Icon:  "ArrowUpDown",
+		})
+	}
 	return &protocol.DataPayload{
 		Items: items,
 	}, nil
 }
+
+// rates computes per-interface throughput for the selected interfaces.
+// Each interface keeps its own previous sample, so one that was just selected
+// (or just appeared) reports 0 until it has a baseline instead of its whole
+// lifetime counter as a single-second spike. Counter resets also report 0.
+func (m *NetModule) rates(counters []net.IOCountersStat, now time.Time) []ifaceRate {
+	selected := m.resolveIfaces(counters)
+
+	next := make(map[string]ifaceSample, len(selected))
+	rates := make([]ifaceRate, 0, len(selected))
+	for _, c := range counters {
+		if !slices.Contains(selected, c.Name) {
+			continue
+		}
+		cur := ifaceSample{recv: c.BytesRecv, sent: c.BytesSent, at: now}
+		next[c.Name] = cur
+
+		r := ifaceRate{name: c.Name}
+		if prev, ok := m.prev[c.Name]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+			if elapsed > 0 && cur.recv >= prev.recv && cur.sent >= prev.sent {
+				r.down = float64(cur.recv-prev.recv) / elapsed
+				r.up = float64(cur.sent-prev.sent) / elapsed
+			}
+		}
+		rates = append(rates, r)
+	}
+	m.prev = next
+	return rates
+}
+
+func (m *NetModule) resolveIfaces(counters []net.IOCountersStat) []string {
+	if len(m.selectedIfaces) > 0 {
+		return m.selectedIfaces
+	}
+
+	// Auto detect physical interfaces
+	var names []string
+	for _, c := range counters {
+		if !isVirtualIface(c.Name) {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// scale converts bytes/s to the configured unit (bytes/s or bits/s).
+func (m *NetModule) scale(bytesPerSec float64) float64 {
+	if m.unit == "bits" {
+		return bytesPerSec * 8
+	}
+	return bytesPerSec
+}
+
+// formatRate renders a value already in the configured unit.
+func (m *NetModule) formatRate(v float64) string {
+	return formatRate(v, m.unit == "bits")
+}
@@ -0,0 +1,122 @@
+package modules
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+func netCounters(eth0Recv, eth0Sent uint64) []net.IOCountersStat {
+	return []net.IOCountersStat{
+		{Name: "lo", BytesRecv: 1 << 30, BytesSent: 1 << 30},
+		{Name: "eth0", BytesRecv: eth0Recv, BytesSent: eth0Sent},
+		{Name: "docker0", BytesRecv: 1 << 20, BytesSent: 1 << 20},
+	}
+}
+
+// --- rates ---
+
+func TestNetModule_Rates_AutoSelectsPhysical(t *testing.T) {
+	m := NewNetModule()
+	t0 := time.Unix(1000, 0)
+
+	first := m.rates(netCounters(1000, 500), t0)
+	if len(first) != 1 || first[0].name != "eth0" {
+		t.Fatalf("want only eth0, got %+v", first)
+	}
+	if first[0].down != 0 || first[0].up != 0 {
+		t.Errorf("first sample should report 0, got %+v", first[0])
+	}
+
+	second := m.rates(netCounters(3000, 1500), t0.Add(2*time.Second))
+	if second[0].down != 1000 || second[0].up != 500 {
+		t.Errorf("want down=1000 up=500, got %+v", second[0])
+	}
+}
+
+func TestIsVirtualIface(t *testing.T) {
+	cases := map[string]bool{
+		"lo":                          true,
+		"lo0":                         true,
+		"Loopback Pseudo-Interface 1": true,
+		"docker0":                     true,
+		"veth12ab":                    true,
+		"eth0":                        false,
+		"Wi-Fi":                       false,
+		"Local Area Connection":       false,
+		"Local Area Connection* 2":    false,
+		"longname0":                   false,
+	}
+	for name, want := range cases {
+		if got := isVirtualIface(name); got != want {
+			t.Errorf("isVirtualIface(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestNetModule_Rates_NoSpikeAfterSelectionChange(t *testing.T) {
+	m := NewNetModule()
+	t0 := time.Unix(1000, 0)
+	m.rates(netCounters(1000, 1000), t0)
+
+	// Selecting lo (1 GiB lifetime counter) must not report 1 GiB/s
+	m.ApplyConfig(map[string]interface{}{"interfaces": []interface{}{"eth0", "lo"}})
+	got := m.rates(netCounters(2000, 2000), t0.Add(time.Second))
+	for _, r := range got {
+		switch r.name {
+		case "lo":
+			if r.down != 0 || r.up != 0 {
+				t.Errorf("newly selected lo should report 0, got %+v", r)
+			}
+		case "eth0":
+			if r.down != 1000 {
+				t.Errorf("eth0 baseline should survive config change, got %+v", r)
+			}
+		}
+	}
+	if len(got) != 2 {
+		t.Errorf("want 2 interfaces, got %+v", got)
+	}
+}
+
+func TestNetModule_Rates_CounterReset(t *testing.T) {
+	m := NewNetModule()
+	t0 := time.Unix(1000, 0)
+	m.rates(netCounters(5000, 5000), t0)
+
+	got := m.rates(netCounters(100, 100), t0.Add(time.Second))
+	if got[0].down != 0 || got[0].up != 0 {
+		t.Errorf("counter reset should report 0, got %+v", got[0])
+	}
+}
+
+// --- Units ---
+
+func TestNetModule_FormatRate(t *testing.T) {
+	m := NewNetModule()
+	cases := map[float64]string{
+		512:             "512 B/s",
+		1536:            "1.5 KB/s",
+		5 * 1024 * 1024: "5.0 MB/s",
+		3 << 30:         "3.0 GB/s",
+	}
+	for in, want := range cases {
+		if got := m.formatRate(m.scale(in)); got != want {
+			t.Errorf("bytes %v: want %q, got %q", in, want, got)
+		}
+	}
+
+	m.ApplyConfig(map[string]interface{}{"unit": "bits"})
+	if got := m.formatRate(m.scale(125000)); got != "1.0 Mb/s" {
+		t.Errorf("bits: want %q, got %q", "1.0 Mb/s", got)
+	}
+}
+
+func TestNetModule_SparklineRenderConfig(t *testing.T) {
+	m := NewNetModule()
+	m.ApplyConfig(map[string]interface{}{"display": "sparkline"})
+	if cfg := m.GetRenderConfig(); cfg.Type != "sparkline" || cfg.Props["series"] == nil {
+		t.Errorf("want sparkline with series, got %+v", cfg)
+	}
+}
@@ -0,0 +1,264 @@
+package modules
+
+import (
+	"fmt"
+	"glancehud/internal/protocol"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Socket types as reported in ConnectionStat.Type (same values on Linux,
+// macOS and Windows).
+const (
+	sockStream = 1 // TCP
+	sockDgram  = 2 // UDP
+)
+
+// tcpStates are the connection states shown in the "states" view, in order.
+var tcpStates = []string{"ESTABLISHED", "TIME_WAIT", "CLOSE_WAIT"}
+
+// PortsModule lists listening sockets with their owning processes, or counts
+// TCP connections by state.
+type PortsModule struct {
+	view   string      // "listening" | "states"
+	ranges []portRange // empty means every port
+}
+
+// portRange is an inclusive range of port numbers.
+type portRange struct {
+	lo, hi uint32
+}
+
+// listener is one listening socket, merged across IPv4/IPv6.
+type listener struct {
+	proto string // "tcp" | "udp"
+	port  uint32
+	pid   int32
+}
+
+func NewPortsModule() *PortsModule {
+	return &PortsModule{
+		view: "listening",
+	}
+}
+
+func (m *PortsModule) ID() string {
+	return "ports"
+}
+
+func (m *PortsModule) Interval() time.Duration {
+	return 5 * time.Second
+}
+
+func (m *PortsModule) ApplyConfig(props map[string]interface{}) {
+	if val, ok := props["view"].(string); ok && (val == "listening" || val == "states") {
+		m.view = val
+	}
+	if val, ok := props["ports"].(string); ok {
+		m.ranges = parsePortRanges(val)
+	}
+}
+
+// parsePortRanges parses "22, 80, 8000-8999" into ranges. Malformed entries
+// are skipped.
+func parsePortRanges(s string) []portRange {
+	var out []portRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		loStr, hiStr, isRange := strings.Cut(part, "-")
+		lo, err := strconv.ParseUint(strings.TrimSpace(loStr), 10, 16)
+		if err != nil {
+			continue
+		}
+		hi := lo
+		if isRange {
+			if hi, err = strconv.ParseUint(strings.TrimSpace(hiStr), 10, 16); err != nil || hi < lo {
+				continue
+			}
+		}
+		out = append(out, portRange{lo: uint32(lo), hi: uint32(hi)})
+	}
+	return out
+}
+
+func inRanges(port uint32, ranges []portRange) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if port >= r.lo && port <= r.hi {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *PortsModule) GetConfigSchema() []protocol.ConfigSchema {
+	return []protocol.ConfigSchema{
+		{
+			Name:    "view",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: "listening",
+			Options: []protocol.SelectOption{
+				{Label: "Listening ports", Value: "listening"},
+				{Label: "TCP connection states", Value: "states"},
+			},
+		},
+		{
+			Name:  "ports",
+			Label: "Ports (e.g. 22, 80, 8000-8999; empty = all)",
+			Type:  protocol.ConfigText,
+		},
+	}
+}
+
+func (m *PortsModule) GetRenderConfig() protocol.RenderConfig {
+	if m.view == "states" {
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.ports",
+			Type:  protocol.TypeBarList,
+			Title: "TCP States",
+		}
+	}
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.ports",
+		Type:  protocol.TypeKeyValue,
+		Title: "Listening",
+		Props: map[string]any{
+			"layout": "column",
+		},
+	}
+}
+
+func (m *PortsModule) Update() (*protocol.DataPayload, error) {
+	conns, err := net.ConnectionsWithContext(hostContext(), "inet")
+	if err != nil {
+		return nil, err
+	}
+
+	if m.view == "states" {
+		counts, total := countTCPStates(conns, m.ranges)
+		items := make([]protocol.BarListItem, 0, len(tcpStates))
+		series := make(map[string]float64, len(tcpStates))
+		for _, state := range tcpStates {
+			n := counts[state]
+			var pct float64
+			if total > 0 {
+				pct = float64(n) / float64(total) * 100
+			}
+			series[strings.ToLower(state)] = float64(n)
+			items = append(items, protocol.BarListItem{
+				Label:   state,
+				Percent: round(pct, 1),
+				Value:   strconv.Itoa(n),
+			})
+		}
+		return &protocol.DataPayload{Value: float64(total), Items: items, Series: series}, nil
+	}
+
+	listeners := findListeners(conns, m.ranges)
+	names := processNames(listeners)
+	items := make([]protocol.KeyValueItem, 0, len(listeners))
+	for _, l := range listeners {
+		owner := "—"
+		if name, ok := names[l.pid]; ok {
+			owner = fmt.Sprintf("%s [%d]", name, l.pid)
+		} else if l.pid > 0 {
+			owner = fmt.Sprintf("[%d]", l.pid)
+		}
+		items = append(items, protocol.KeyValueItem{
+			Key:   fmt.Sprintf("%s :%d", l.proto, l.port),
+			Value: owner,
+			Icon:  "Network",
+		})
+	}
+	return &protocol.DataPayload{Value: float64(len(listeners)), Items: items}, nil
+}
+
+// findListeners returns listening TCP sockets and unconnected UDP sockets,
+// one per protocol/port/process, sorted by protocol then port. A service bound
+// to both 0.0.0.0 and :: appears once.
+func findListeners(conns []net.ConnectionStat, ranges []portRange) []listener {
+	seen := make(map[listener]bool)
+	var out []listener
+	for _, c := range conns {
+		var proto string
+		switch {
+		case c.Type == sockStream && c.Status == "LISTEN":
+			proto = "tcp"
+		case c.Type == sockDgram && c.Raddr.Port == 0:
+			proto = "udp"
+		default:
+			continue
+		}
+		if c.Laddr.Port == 0 || !inRanges(c.Laddr.Port, ranges) {
+			continue
+		}
+		l := listener{proto: proto, port: c.Laddr.Port, pid: c.Pid}
+		if !seen[l] {
+			seen[l] = true
+			out = append(out, l)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].proto != out[j].proto {
+			return out[i].proto < out[j].proto
+		}
+		if out[i].port != out[j].port {
+			return out[i].port < out[j].port
+		}
+		return out[i].pid < out[j].pid
+	})
+	return out
+}
+
+// countTCPStates counts TCP connections by state. With port ranges, a
+// connection counts when either end's port is in range, so both a local
+// server and the remote service a client talks to can be watched.
+func countTCPStates(conns []net.ConnectionStat, ranges []portRange) (map[string]int, int) {
+	counts := make(map[string]int)
+	total := 0
+	for _, c := range conns {
+		if c.Type != sockStream || c.Status == "LISTEN" {
+			continue
+		}
+		if len(ranges) > 0 && !inRanges(c.Laddr.Port, ranges) && !inRanges(c.Raddr.Port, ranges) {
+			continue
+		}
+		counts[c.Status]++
+		total++
+	}
+	return counts, total
+}
+
+// processNames resolves listener PIDs to executable names. PIDs that vanish
+// or deny access (other users' processes without privileges) are omitted.
+func processNames(listeners []listener) map[int32]string {
+	ctx := hostContext()
+	names := make(map[int32]string)
+	for _, l := range listeners {
+		if l.pid <= 0 {
+			continue
+		}
+		if _, done := names[l.pid]; done {
+			continue
+		}
+		p, err := process.NewProcessWithContext(ctx, l.pid)
+		if err != nil {
+			continue
+		}
+		if name, err := p.NameWithContext(ctx); err == nil {
+			names[l.pid] = name
+		}
+	}
+	return names
+}
@@ -0,0 +1,67 @@
+package modules
+
+import (
+	"testing"
+
+	"github.com/shirou/gopsutil/v4/net"
+)
+
+func testConns() []net.ConnectionStat {
+	return []net.ConnectionStat{
+		{Type: sockStream, Status: "LISTEN", Laddr: net.Addr{IP: "0.0.0.0", Port: 5432}, Pid: 100},
+		{Type: sockStream, Status: "LISTEN", Laddr: net.Addr{IP: "::", Port: 5432}, Pid: 100},
+		{Type: sockStream, Status: "LISTEN", Laddr: net.Addr{IP: "127.0.0.1", Port: 22}, Pid: 1},
+		{Type: sockDgram, Status: "NONE", Laddr: net.Addr{IP: "0.0.0.0", Port: 53}, Pid: 50},
+		{Type: sockDgram, Status: "NONE", Laddr: net.Addr{IP: "10.0.0.2", Port: 40000}, Raddr: net.Addr{IP: "1.1.1.1", Port: 53}},
+		{Type: sockStream, Status: "ESTABLISHED", Laddr: net.Addr{Port: 5432}, Raddr: net.Addr{Port: 50001}},
+		{Type: sockStream, Status: "ESTABLISHED", Laddr: net.Addr{Port: 50002}, Raddr: net.Addr{Port: 443}},
+		{Type: sockStream, Status: "TIME_WAIT", Laddr: net.Addr{Port: 50003}, Raddr: net.Addr{Port: 443}},
+		{Type: sockStream, Status: "CLOSE_WAIT", Laddr: net.Addr{Port: 5432}, Raddr: net.Addr{Port: 50004}},
+	}
+}
+
+func TestParsePortRanges(t *testing.T) {
+	got := parsePortRanges("22, 8000-8999, bad, 90-80, 70000, 443")
+	want := []portRange{{22, 22}, {8000, 8999}, {443, 443}}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] want %v, got %v", i, want[i], got[i])
+		}
+	}
+	if !inRanges(8080, got) || inRanges(9000, got) || !inRanges(1, nil) {
+		t.Error("inRanges mismatch")
+	}
+}
+
+func TestFindListeners(t *testing.T) {
+	got := findListeners(testConns(), nil)
+	want := []listener{{"tcp", 22, 1}, {"tcp", 5432, 100}, {"udp", 53, 50}}
+	if len(got) != len(want) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("[%d] want %v, got %v", i, want[i], got[i])
+		}
+	}
+
+	got = findListeners(testConns(), parsePortRanges("5000-6000"))
+	if len(got) != 1 || got[0].port != 5432 {
+		t.Errorf("want only 5432, got %v", got)
+	}
+}
+
+func TestCountTCPStates(t *testing.T) {
+	counts, total := countTCPStates(testConns(), nil)
+	if total != 4 || counts["ESTABLISHED"] != 2 || counts["TIME_WAIT"] != 1 || counts["CLOSE_WAIT"] != 1 {
+		t.Errorf("unexpected counts %v total=%d", counts, total)
+	}
+
+	counts, total = countTCPStates(testConns(), parsePortRanges("443"))
+	if total != 2 || counts["ESTABLISHED"] != 1 || counts["TIME_WAIT"] != 1 {
+		t.Errorf("remote port filter: got %v total=%d", counts, total)
+	}
+}
@@ -0,0 +1,322 @@
+package modules
+
+import (
+	"fmt"
+	"glancehud/internal/protocol"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// ProcessModule ranks running processes by CPU or resident memory.
+type ProcessModule struct {
+	sortBy      string // "cpu" | "mem"
+	topN        int
+	include     []string // lower-case name substrings; empty means all
+	exclude     []string
+	groupByName bool
+	minimalMode bool
+
+	prevTimes map[int32]procCPUTime
+	prevTime  time.Time
+}
+
+// procCPUTime is the last seen cumulative CPU time of a process. createTime
+// guards against PID reuse between samples.
+type procCPUTime struct {
+	total      float64
+	createTime int64
+}
+
+// procSample is one process (or group of processes) ready for ranking.
+type procSample struct {
+	pid   int32
+	name  string
+	count int     // processes in the group (1 when not grouped)
+	cpu   float64 // share of total CPU capacity, 0~100
+	rss   uint64
+}
+
+func NewProcessModule() *ProcessModule {
+	return &ProcessModule{
+		sortBy:    "cpu",
+		topN:      5,
+		prevTimes: make(map[int32]procCPUTime),
+	}
+}
+
+func (m *ProcessModule) ID() string {
+	return "proc"
+}
+
+func (m *ProcessModule) Interval() time.Duration {
+	return 3 * time.Second
+}
+
+func (m *ProcessModule) ApplyConfig(props map[string]interface{}) {
+	if val, ok := props["sort_by"].(string); ok && (val == "cpu" || val == "mem") {
+		m.sortBy = val
+	}
+	if val, ok := props["top_n"].(float64); ok && val >= 1 {
+		m.topN = int(val)
+	}
+	if val, ok := props["include"].(string); ok {
+		m.include = splitFilter(val)
+	}
+	if val, ok := props["exclude"].(string); ok {
+		m.exclude = splitFilter(val)
+	}
+	if val, ok := props["group_by_name"].(bool); ok {
+		m.groupByName = val
+	}
+	if val, ok := props["minimal_mode"].(bool); ok {
+		m.minimalMode = val
+	}
+}
+
+// splitFilter parses a comma-separated filter list into lower-case terms.
+func splitFilter(s string) []string {
+	var terms []string
+	for _, t := range strings.Split(s, ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			terms = append(terms, t)
+		}
+	}
+	return terms
+}
+
+func (m *ProcessModule) GetConfigSchema() []protocol.ConfigSchema {
+	return []protocol.ConfigSchema{
+		{
+			Name:    "sort_by",
+			Label:   "Sort By",
+			Type:    protocol.ConfigSelect,
+			Default: "cpu",
+			Options: []protocol.SelectOption{
+				{Label: "CPU", Value: "cpu"},
+				{Label: "Memory (RSS)", Value: "mem"},
+			},
+		},
+		{
+			Name:    "top_n",
+			Label:   "Top N",
+			Type:    protocol.ConfigNumber,
+			Default: 5,
+		},
+		{
+			Name:  "include",
+			Label: "Include (comma-separated names)",
+			Type:  protocol.ConfigText,
+		},
+		{
+			Name:  "exclude",
+			Label: "Exclude (comma-separated names)",
+			Type:  protocol.ConfigText,
+		},
+		{
+			Name:    "group_by_name",
+			Label:   "Group by Executable",
+			Type:    protocol.ConfigBool,
+			Default: false,
+		},
+	}
+}
+
+func (m *ProcessModule) GetRenderConfig() protocol.RenderConfig {
+	title := "Top CPU"
+	if m.sortBy == "mem" {
+		title = "Top Memory"
+	}
+	if m.minimalMode {
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.proc",
+			Type:  protocol.TypeKeyValue,
+			Title: title,
+			Props: map[string]any{
+				"layout": "column",
+			},
+		}
+	}
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.proc",
+		Type:  protocol.TypeBarList,
+		Title: title,
+	}
+}
+
+func (m *ProcessModule) Update() (*protocol.DataPayload, error) {
+	samples, err := m.collect()
+	if err != nil {
+		return nil, err
+	}
+
+	var totalMem uint64
+	if vm, err := mem.VirtualMemoryWithContext(hostContext()); err == nil {
+		totalMem = vm.Total
+	}
+
+	ranked := rankProcesses(samples, m.sortBy, m.topN, m.include, m.exclude, m.groupByName)
+
+	if m.minimalMode {
+		items := make([]protocol.KeyValueItem, 0, len(ranked))
+		for _, p := range ranked {
+			items = append(items, protocol.KeyValueItem{
+				Key:   procLabel(p, m.groupByName),
+				Value: procValue(p, m.sortBy),
+				Icon:  "Activity",
+			})
+		}
+		return &protocol.DataPayload{Items: items}, nil
+	}
+
+	items := make([]protocol.BarListItem, 0, len(ranked))
+	for _, p := range ranked {
+		percent := p.cpu
+		if m.sortBy == "mem" {
+			percent = 0
+			if totalMem > 0 {
+				percent = float64(p.rss) / float64(totalMem) * 100
+			}
+		}
+		items = append(items, protocol.BarListItem{
+			Label:   procLabel(p, m.groupByName),
+			Percent: round(percent, 1),
+			Value:   procValue(p, m.sortBy),
+		})
+	}
+	return &protocol.DataPayload{Items: items}, nil
+}
+
+// collect samples every visible process. CPU usage is the delta of cumulative
+// CPU time since the previous call, so the first call reports 0 for all.
+// Processes that vanish or deny access mid-scan are skipped.
+func (m *ProcessModule) collect() ([]procSample, error) {
+	ctx := hostContext()
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var elapsed float64
+	if !m.prevTime.IsZero() {
+		elapsed = now.Sub(m.prevTime).Seconds()
+	}
+	capacity := elapsed * float64(runtime.NumCPU())
+
+	nextTimes := make(map[int32]procCPUTime, len(procs))
+	samples := make([]procSample, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.NameWithContext(ctx)
+		if err != nil || name == "" {
+			continue
+		}
+		s := procSample{pid: p.Pid, name: name, count: 1}
+
+		if times, err := p.TimesWithContext(ctx); err == nil {
+			created, _ := p.CreateTimeWithContext(ctx)
+			cur := procCPUTime{total: times.User + times.System, createTime: created}
+			nextTimes[p.Pid] = cur
+			if prev, ok := m.prevTimes[p.Pid]; ok && prev.createTime == created && capacity > 0 {
+				if delta := cur.total - prev.total; delta > 0 {
+					s.cpu = delta / capacity * 100
+				}
+			}
+		}
+		if mi, err := p.MemoryInfoWithContext(ctx); err == nil {
+			s.rss = mi.RSS
+		}
+		samples = append(samples, s)
+	}
+
+	m.prevTimes = nextTimes
+	m.prevTime = now
+	return samples, nil
+}
+
+// rankProcesses filters, optionally groups by name, sorts and truncates.
+func rankProcesses(samples []procSample, sortBy string, topN int, include, exclude []string, group bool) []procSample {
+	filtered := make([]procSample, 0, len(samples))
+	for _, s := range samples {
+		if matchesFilter(s.name, include, exclude) {
+			filtered = append(filtered, s)
+		}
+	}
+
+	if group {
+		byName := make(map[string]*procSample)
+		var order []string
+		for _, s := range filtered {
+			g, ok := byName[s.name]
+			if !ok {
+				g = &procSample{pid: s.pid, name: s.name}
+				byName[s.name] = g
+				order = append(order, s.name)
+			}
+			g.count++
+			g.cpu += s.cpu
+			g.rss += s.rss
+		}
+		filtered = filtered[:0]
+		for _, name := range order {
+			filtered = append(filtered, *byName[name])
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if sortBy == "mem" {
+			if a.rss != b.rss {
+				return a.rss > b.rss
+			}
+		} else if a.cpu != b.cpu {
+			return a.cpu > b.cpu
+		}
+		return a.name < b.name
+	})
+
+	if topN > 0 && len(filtered) > topN {
+		filtered = filtered[:topN]
+	}
+	return filtered
+}
+
+func matchesFilter(name string, include, exclude []string) bool {
+	lower := strings.ToLower(name)
+	for _, t := range exclude {
+		if strings.Contains(lower, t) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, t := range include {
+		if strings.Contains(lower, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// procLabel keeps labels unique: the bar-list renderer keys rows by label.
+func procLabel(p procSample, grouped bool) string {
+	if grouped {
+		if p.count > 1 {
+			return fmt.Sprintf("%s ×%d", p.name, p.count)
+		}
+		return p.name
+	}
+	return fmt.Sprintf("%s [%d]", p.name, p.pid)
+}
+
+func procValue(p procSample, sortBy string) string {
+	if sortBy == "mem" {
+		return formatBytes(p.rss)
+	}
+	return fmt.Sprintf("%.1f%%", p.cpu)
+}
@@ -0,0 +1,111 @@
+package modules
+
+import (
+	"glancehud/internal/protocol"
+	"testing"
+)
+
+func testProcSamples() []procSample {
+	return []procSample{
+		{pid: 1, name: "systemd", count: 1, cpu: 0.1, rss: 10 << 20},
+		{pid: 100, name: "chrome", count: 1, cpu: 12, rss: 300 << 20},
+		{pid: 101, name: "chrome", count: 1, cpu: 8, rss: 200 << 20},
+		{pid: 200, name: "postgres", count: 1, cpu: 15, rss: 100 << 20},
+		{pid: 300, name: "Xorg", count: 1, cpu: 3, rss: 150 << 20},
+	}
+}
+
+// --- rankProcesses ---
+
+func TestRankProcesses_ByCPU(t *testing.T) {
+	got := rankProcesses(testProcSamples(), "cpu", 3, nil, nil, false)
+	want := []int32{200, 100, 101}
+	if len(got) != len(want) {
+		t.Fatalf("want %d results, got %d", len(want), len(got))
+	}
+	for i, p := range got {
+		if p.pid != want[i] {
+			t.Errorf("position %d: want pid %d, got %d", i, want[i], p.pid)
+		}
+	}
+}
+
+func TestRankProcesses_ByMemGrouped(t *testing.T) {
+	got := rankProcesses(testProcSamples(), "mem", 2, nil, nil, true)
+	if len(got) != 2 {
+		t.Fatalf("want 2 results, got %d", len(got))
+	}
+	if got[0].name != "chrome" || got[0].count != 2 || got[0].rss != 500<<20 || got[0].cpu != 20 {
+		t.Errorf("want grouped chrome first, got %+v", got[0])
+	}
+	if got[1].name != "Xorg" {
+		t.Errorf("want Xorg second, got %+v", got[1])
+	}
+	if label := procLabel(got[0], true); label != "chrome ×2" {
+		t.Errorf("want label %q, got %q", "chrome ×2", label)
+	}
+}
+
+func TestRankProcesses_Filters(t *testing.T) {
+	got := rankProcesses(testProcSamples(), "cpu", 10, splitFilter("chrome, XORG"), splitFilter("xorg"), false)
+	if len(got) != 2 {
+		t.Fatalf("want 2 chrome processes, got %+v", got)
+	}
+	for _, p := range got {
+		if p.name != "chrome" {
+			t.Errorf("unexpected process %q", p.name)
+		}
+	}
+}
+
+// --- ProcessModule ---
+
+func TestProcessModule_ApplyConfig(t *testing.T) {
+	m := NewProcessModule()
+	m.ApplyConfig(map[string]interface{}{
+		"sort_by":       "mem",
+		"top_n":         float64(8),
+		"include":       " Foo ,,bar",
+		"group_by_name": true,
+	})
+	if m.sortBy != "mem" || m.topN != 8 || !m.groupByName {
+		t.Errorf("unexpected config: %+v", m)
+	}
+	if len(m.include) != 2 || m.include[0] != "foo" || m.include[1] != "bar" {
+		t.Errorf("want [foo bar], got %v", m.include)
+	}
+
+	// Invalid values are ignored
+	m.ApplyConfig(map[string]interface{}{"sort_by": "io", "top_n": float64(0)})
+	if m.sortBy != "mem" || m.topN != 8 {
+		t.Errorf("invalid values should be ignored, got sortBy=%q topN=%d", m.sortBy, m.topN)
+	}
+}
+
+func TestProcessModule_Update(t *testing.T) {
+	m := NewProcessModule()
+	if _, err := m.Update(); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	data, err := m.Update()
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if _, ok := data.Items.([]protocol.BarListItem); !ok {
+		t.Errorf("want []BarListItem, got %T", data.Items)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[uint64]string{
+		512:       "512 B",
+		2048:      "2.0 KB",
+		300 << 20: "300.0 MB",
+		3 << 30:   "3.0 GB",
+	}
+	for in, want := range cases {
+		if got := formatBytes(in); got != want {
+			t.Errorf("formatBytes(%d): want %q, got %q", in, want, got)
+		}
+	}
+}
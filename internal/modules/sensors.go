@@ -0,0 +1,353 @@
+package modules
+
+import (
+	"fmt"
+	"glancehud/internal/protocol"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/sensors"
+)
+
+// Fallback thresholds (°C) for sensors that do not report their own
+// temp*_max / temp*_crit values.
+const (
+	defaultTempHigh     = 80.0
+	defaultTempCritical = 100.0
+)
+
+// fanKeyPrefix distinguishes fan sensors from temperature sensors in the
+// checkbox list; gopsutil only covers temperatures, fans come from hwmon.
+const fanKeyPrefix = "fan:"
+
+// SensorsModule reports hardware temperatures and fan speeds.
+type SensorsModule struct {
+	selected    []string // Sensor keys; empty means all
+	unit        string   // "c" | "f"
+	display     string   // "bar-list" | "gauge"
+	minimalMode bool
+}
+
+// sensorReading is one temperature (°C) or fan (RPM) reading.
+type sensorReading struct {
+	key      string
+	fan      bool
+	value    float64
+	high     float64 // temperatures: warning level; fans: max RPM (0 if unknown)
+	critical float64 // temperatures only
+}
+
+func NewSensorsModule() *SensorsModule {
+	return &SensorsModule{
+		unit:    "c",
+		display: "bar-list",
+	}
+}
+
+func (m *SensorsModule) ID() string {
+	return "sensors"
+}
+
+func (m *SensorsModule) Interval() time.Duration {
+	return 5 * time.Second
+}
+
+func (m *SensorsModule) ApplyConfig(props map[string]interface{}) {
+	// "sensors" is a []interface{} from JSON deserialization
+	if val, ok := props["sensors"].([]interface{}); ok {
+		m.selected = nil
+		for _, v := range val {
+			if s, ok := v.(string); ok {
+				m.selected = append(m.selected, s)
+			}
+		}
+	}
+	if val, ok := props["unit"].(string); ok && (val == "c" || val == "f") {
+		m.unit = val
+	}
+	if val, ok := props["display"].(string); ok && (val == "bar-list" || val == "gauge") {
+		m.display = val
+	}
+	if val, ok := props["minimal_mode"].(bool); ok {
+		m.minimalMode = val
+	}
+}
+
+func (m *SensorsModule) GetConfigSchema() []protocol.ConfigSchema {
+	readings := readSensors()
+	options := make([]protocol.SelectOption, 0, len(readings))
+	defaults := make([]string, 0, len(readings))
+	for _, r := range readings {
+		options = append(options, protocol.SelectOption{Label: r.key, Value: r.key})
+		defaults = append(defaults, r.key)
+	}
+
+	return []protocol.ConfigSchema{
+		{
+			Name:    "sensors",
+			Label:   "顯示感測器",
+			Type:    protocol.ConfigCheckboxes,
+			Default: defaults,
+			Options: options,
+		},
+		{
+			Name:    "unit",
+			Label:   "Unit",
+			Type:    protocol.ConfigSelect,
+			Default: "c",
+			Options: []protocol.SelectOption{
+				{Label: "Celsius (°C)", Value: "c"},
+				{Label: "Fahrenheit (°F)", Value: "f"},
+			},
+		},
+		{
+			Name:    "display",
+			Label:   "Display",
+			Type:    protocol.ConfigSelect,
+			Default: "bar-list",
+			Options: []protocol.SelectOption{
+				{Label: "Per-sensor bars", Value: "bar-list"},
+				{Label: "Gauge (hottest sensor)", Value: "gauge"},
+			},
+		},
+	}
+}
+
+func (m *SensorsModule) GetRenderConfig() protocol.RenderConfig {
+	if m.minimalMode {
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.sensors",
+			Type:  protocol.TypeKeyValue,
+			Title: "Temp",
+			Props: map[string]any{
+				"layout": "row",
+			},
+		}
+	}
+	if m.display == "gauge" {
+		return protocol.RenderConfig{
+			ID:    "glancehud.core.sensors",
+			Type:  protocol.TypeGauge,
+			Title: "Temp",
+			Props: map[string]any{
+				"unit": m.unitSuffix(),
+			},
+		}
+	}
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.sensors",
+		Type:  protocol.TypeBarList,
+		Title: "Sensors",
+	}
+}
+
+func (m *SensorsModule) Update() (*protocol.DataPayload, error) {
+	readings := m.filter(readSensors())
+
+	hottest, ok := hottestSensor(readings)
+	if m.minimalMode {
+		if !ok {
+			return &protocol.DataPayload{
+				Items: []protocol.KeyValueItem{{Key: "Temp", Value: "—", Icon: "Thermometer"}},
+			}, nil
+		}
+		return &protocol.DataPayload{
+			Value: round(m.convert(hottest.value), 1),
+			Items: []protocol.KeyValueItem{{Key: "Temp", Value: m.formatTemp(hottest.value), Icon: "Thermometer"}},
+		}, nil
+	}
+
+	if m.display == "gauge" {
+		if !ok {
+			return &protocol.DataPayload{Value: 0}, nil
+		}
+		// The ring spans 0 → the sensor's own critical level
+		return &protocol.DataPayload{
+			Value: round(m.convert(hottest.value), 1),
+			Label: hottest.key,
+			Props: map[string]any{
+				"max":   round(m.convert(hottest.critical), 1),
+				"color": tempColor(hottest),
+			},
+		}, nil
+	}
+
+	if len(readings) == 0 {
+		return &protocol.DataPayload{
+			Items: []protocol.BarListItem{{Label: "No sensors detected", Value: "—"}},
+		}, nil
+	}
+	items := make([]protocol.BarListItem, 0, len(readings))
+	for _, r := range readings {
+		if r.fan {
+			item := protocol.BarListItem{
+				Label: strings.TrimPrefix(r.key, fanKeyPrefix),
+				Value: fmt.Sprintf("%.0f RPM", r.value),
+			}
+			if r.high > 0 {
+				item.Percent = round(min(100, r.value/r.high*100), 1)
+			}
+			items = append(items, item)
+			continue
+		}
+		items = append(items, protocol.BarListItem{
+			Label:   r.key,
+			Percent: round(min(100, r.value/r.critical*100), 1),
+			Value:   m.formatTemp(r.value),
+			Color:   tempColor(r),
+		})
+	}
+	return &protocol.DataPayload{Items: items}, nil
+}
+
+func (m *SensorsModule) filter(readings []sensorReading) []sensorReading {
+	if len(m.selected) == 0 {
+		return readings
+	}
+	out := readings[:0]
+	for _, r := range readings {
+		for _, s := range m.selected {
+			if r.key == s {
+				out = append(out, r)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// hottestSensor picks the temperature closest to its own critical level,
+// which is what matters for throttling, rather than the highest raw value.
+func hottestSensor(readings []sensorReading) (sensorReading, bool) {
+	best := sensorReading{critical: defaultTempCritical}
+	found := false
+	for _, r := range readings {
+		if r.fan {
+			continue
+		}
+		if !found || r.value/r.critical > best.value/best.critical {
+			best, found = r, true
+		}
+	}
+	return best, found
+}
+
+func tempColor(r sensorReading) string {
+	switch {
+	case r.value >= r.critical:
+		return "#ef4444"
+	case r.value >= r.high:
+		return "#f59e0b"
+	default:
+		return "#22c55e"
+	}
+}
+
+func (m *SensorsModule) convert(celsius float64) float64 {
+	if m.unit == "f" {
+		return celsius*9/5 + 32
+	}
+	return celsius
+}
+
+func (m *SensorsModule) unitSuffix() string {
+	if m.unit == "f" {
+		return "°F"
+	}
+	return "°C"
+}
+
+func (m *SensorsModule) formatTemp(celsius float64) string {
+	return fmt.Sprintf("%.0f%s", m.convert(celsius), m.unitSuffix())
+}
+
+// readSensors returns every temperature and fan sensor, sorted by key.
+// Platforms without sensor support (or without permission) yield nothing
+// rather than an error, so the widget shows "No sensors detected" instead of
+// going offline.
+func readSensors() []sensorReading {
+	// Unreadable sensors come back as *sensors.Warnings alongside the ones
+	// that did read, so the error alone is not a reason to drop the list.
+	temps, _ := sensors.TemperaturesWithContext(hostContext())
+
+	readings := make([]sensorReading, 0, len(temps))
+	for _, t := range temps {
+		r := sensorReading{key: t.SensorKey, value: t.Temperature, high: t.High, critical: t.Critical}
+		if r.critical <= 0 {
+			r.critical = defaultTempCritical
+		}
+		if r.high <= 0 || r.high > r.critical {
+			r.high = min(defaultTempHigh, r.critical)
+		}
+		readings = append(readings, r)
+	}
+	readings = append(readings, readFans()...)
+
+	sort.SliceStable(readings, func(i, j int) bool { return readings[i].key < readings[j].key })
+	return uniqueSensorKeys(readings)
+}
+
+// uniqueSensorKeys suffixes duplicate keys ("nvme_composite", "nvme_composite
+// #2"); two identical drives report the same hwmon name and label, and both
+// the checkbox list and the bar-list renderer key rows by it.
+func uniqueSensorKeys(readings []sensorReading) []sensorReading {
+	seen := make(map[string]int, len(readings))
+	for i := range readings {
+		key := readings[i].key
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			readings[i].key = fmt.Sprintf("%s #%d", key, n)
+		}
+	}
+	return readings
+}
+
+// readFans reads hwmon fan*_input files (RPM). Only Linux exposes these; the
+// glob simply matches nothing elsewhere.
+func readFans() []sensorReading {
+	files, _ := filepath.Glob(hostSys("class/hwmon/hwmon*/fan*_input"))
+	if len(files) == 0 {
+		// Some drivers nest attributes under device/, as with temperatures
+		files, _ = filepath.Glob(hostSys("class/hwmon/hwmon*/device/fan*_input"))
+	}
+
+	var fans []sensorReading
+	for _, file := range files {
+		rpm, ok := readSysfsFloat(file)
+		if !ok {
+			continue
+		}
+		dir := filepath.Dir(file)
+		base := strings.TrimSuffix(filepath.Base(file), "_input") // "fan1"
+
+		name := base
+		if chip, err := os.ReadFile(filepath.Join(dir, "name")); err == nil {
+			name = strings.TrimSpace(string(chip)) + "_" + base
+		}
+		if label, err := os.ReadFile(filepath.Join(dir, base+"_label")); err == nil {
+			if l := strings.TrimSpace(string(label)); l != "" {
+				name = strings.TrimSuffix(name, base) + strings.ReplaceAll(strings.ToLower(l), " ", "_")
+			}
+		}
+
+		r := sensorReading{key: fanKeyPrefix + name, fan: true, value: rpm}
+		if maxRPM, ok := readSysfsFloat(filepath.Join(dir, base+"_max")); ok {
+			r.high = maxRPM
+		}
+		fans = append(fans, r)
+	}
+	return fans
+}
+
+func readSysfsFloat(path string) (float64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.TrimSpace(string(data)), 64)
+	return v, err == nil
+}
@@ -0,0 +1,137 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"glancehud/internal/protocol"
+)
+
+// writeHwmon builds a fake /sys/class/hwmon/<chip> directory.
+func writeHwmon(t *testing.T, root, chip string, files map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, "class", "hwmon", chip)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func fakeSensors(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	writeHwmon(t, root, "hwmon0", map[string]string{
+		"name":        "coretemp",
+		"temp1_label": "Package id 0",
+		"temp1_input": "85000",
+		"temp1_max":   "80000",
+		"temp1_crit":  "100000",
+		"temp2_label": "Core 0",
+		"temp2_input": "50000",
+	})
+	writeHwmon(t, root, "hwmon1", map[string]string{
+		"name":        "nct6775",
+		"fan1_input":  "1200",
+		"fan1_max":    "2400",
+		"fan2_label":  "CPU Fan",
+		"fan2_input":  "900",
+		"temp1_input": "55000",
+		"temp1_crit":  "60000",
+	})
+	t.Setenv("HOST_SYS", root)
+}
+
+func TestReadSensors_FakeHwmon(t *testing.T) {
+	fakeSensors(t)
+
+	got := readSensors()
+	want := []string{"coretemp_core_0", "coretemp_package_id_0", "fan:nct6775_cpu_fan", "fan:nct6775_fan1", "nct6775"}
+	if len(got) != len(want) {
+		t.Fatalf("want %d sensors, got %+v", len(want), got)
+	}
+	for i, key := range want {
+		if got[i].key != key {
+			t.Errorf("[%d] want %s, got %s", i, key, got[i].key)
+		}
+	}
+
+	pkg := got[1]
+	if pkg.value != 85 || pkg.high != 80 || pkg.critical != 100 {
+		t.Errorf("want 85/80/100, got %+v", pkg)
+	}
+	core := got[0]
+	if core.high != defaultTempHigh || core.critical != defaultTempCritical {
+		t.Errorf("sensor without limits should use defaults, got %+v", core)
+	}
+	if fan := got[3]; !fan.fan || fan.value != 1200 || fan.high != 2400 {
+		t.Errorf("want fan 1200/2400 RPM, got %+v", fan)
+	}
+}
+
+func TestSensorsModule_Update_BarList(t *testing.T) {
+	fakeSensors(t)
+
+	m := NewSensorsModule()
+	m.ApplyConfig(map[string]interface{}{
+		"sensors": []interface{}{"coretemp_package_id_0", "fan:nct6775_fan1"},
+		"unit":    "f",
+	})
+	payload, err := m.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	items := payload.Items.([]protocol.BarListItem)
+	if len(items) != 2 {
+		t.Fatalf("want 2 items, got %+v", items)
+	}
+	if items[0].Value != "185°F" || items[0].Percent != 85 || items[0].Color != "#f59e0b" {
+		t.Errorf("unexpected temperature row %+v", items[0])
+	}
+	if items[1].Label != "nct6775_fan1" || items[1].Value != "1200 RPM" || items[1].Percent != 50 {
+		t.Errorf("unexpected fan row %+v", items[1])
+	}
+}
+
+func TestSensorsModule_Update_Gauge(t *testing.T) {
+	fakeSensors(t)
+
+	m := NewSensorsModule()
+	m.ApplyConfig(map[string]interface{}{"display": "gauge"})
+	payload, err := m.Update()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// nct6775 at 55/60 is closer to critical than coretemp at 85/100
+	if payload.Value != 55.0 || payload.Props["max"] != 60.0 {
+		t.Errorf("want nct6775 55 of 60, got value=%v props=%v", payload.Value, payload.Props)
+	}
+}
+
+func TestSensorsModule_NoSensors(t *testing.T) {
+	t.Setenv("HOST_SYS", t.TempDir())
+
+	m := NewSensorsModule()
+	if schema := m.GetConfigSchema(); len(schema[0].Options) != 0 {
+		t.Errorf("want no options, got %+v", schema[0].Options)
+	}
+	payload, err := m.Update()
+	if err != nil {
+		t.Fatalf("missing sensors should not be an error: %v", err)
+	}
+	items := payload.Items.([]protocol.BarListItem)
+	if len(items) != 1 || items[0].Label != "No sensors detected" {
+		t.Errorf("want placeholder row, got %+v", items)
+	}
+}
+
+func TestUniqueSensorKeys(t *testing.T) {
+	got := uniqueSensorKeys([]sensorReading{{key: "nvme_composite"}, {key: "nvme_composite"}, {key: "x"}})
+	if got[0].key != "nvme_composite" || got[1].key != "nvme_composite #2" || got[2].key != "x" {
+		t.Errorf("unexpected keys %+v", got)
+	}
+}
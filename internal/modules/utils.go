@@ -1,8 +1,78 @@
 package modules
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// SeriesPalette colors successive lines of a multi-series sparkline whose
+// series are only known at runtime.
+var SeriesPalette = []string{"#22c55e", "#3b82f6", "#f59e0b", "#a855f7", "#ec4899", "#14b8a6"}
+
+// NeutralColor is the fixed line color for sparklines whose values are not
+// percentages, which the renderer would otherwise color by 60/85% thresholds.
+const NeutralColor = "#3b82f6"
 
 func round(val float64, n int) float64 {
 	pow := math.Pow(10, float64(n))
 	return math.Round(val*pow) / pow
 }
+
+// formatBytes renders a byte count with a binary-prefixed unit.
+func formatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit && exp < 4; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "KMGTP"[exp])
+}
+
+// formatRate renders a per-second rate with an auto-scaled prefix: binary
+// (KB/s, MB/s) for bytes, decimal (Kb/s, Mb/s) for bits.
+func formatRate(v float64, bits bool) string {
+	base, units := 1024.0, []string{"B/s", "KB/s", "MB/s", "GB/s"}
+	if bits {
+		base, units = 1000.0, []string{"b/s", "Kb/s", "Mb/s", "Gb/s"}
+	}
+	i := 0
+	for v >= base && i < len(units)-1 {
+		v /= base
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", v, units[i])
+	}
+	return fmt.Sprintf("%.1f %s", v, units[i])
+}
+
+// formatDuration renders a coarse, human-friendly duration: "45m", "3h", "2d".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%.0fm", max(1, math.Round(d.Minutes())))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%.0fh", math.Round(d.Hours()))
+	default:
+		return fmt.Sprintf("%.0fd", math.Round(d.Hours()/24))
+	}
+}
+
+// formatCount renders a large count with a k/M/G suffix.
+func formatCount(n uint64) string {
+	switch {
+	case n >= 1e9:
+		return fmt.Sprintf("%.1fG", float64(n)/1e9)
+	case n >= 1e6:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 1e3:
+		return fmt.Sprintf("%.1fk", float64(n)/1e3)
+	default:
+		return fmt.Sprintf("%d", n)
+	}
+}
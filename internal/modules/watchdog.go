@@ -0,0 +1,335 @@
+package modules
+
+import (
+	"fmt"
+	"glancehud/internal/protocol"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// Watchdog matcher kinds.
+const (
+	watchByName    = "name"    // exact executable name, case-insensitive
+	watchByCmdline = "cmdline" // regular expression against the full command line
+	watchByPidfile = "pidfile" // path to a file holding the PID
+)
+
+// WatchdogModule reports whether named services are running, and how often
+// they have restarted since GlanceHUD started watching.
+type WatchdogModule struct {
+	matchers  []watchMatcher
+	downAfter time.Duration // 0 disables the "down" flag
+
+	state map[string]*watchState // by matcher label
+}
+
+// watchMatcher is one parsed entry of the "watch" prop.
+type watchMatcher struct {
+	label   string
+	kind    string
+	pattern string
+	re      *regexp.Regexp // cmdline only
+}
+
+// watchState tracks one matcher across Update calls.
+type watchState struct {
+	pid       int32
+	created   int64 // ms since epoch, from the process
+	restarts  int
+	downSince time.Time // zero while up
+}
+
+// watchProc is the subset of a process the matchers look at.
+type watchProc struct {
+	pid     int32
+	name    string
+	cmdline string
+	created int64
+}
+
+func NewWatchdogModule() *WatchdogModule {
+	return &WatchdogModule{
+		state: make(map[string]*watchState),
+	}
+}
+
+func (m *WatchdogModule) ID() string {
+	return "watchdog"
+}
+
+func (m *WatchdogModule) Interval() time.Duration {
+	return 5 * time.Second
+}
+
+func (m *WatchdogModule) ApplyConfig(props map[string]interface{}) {
+	if val, ok := props["watch"].(string); ok {
+		m.matchers = parseWatchList(val)
+	}
+	if val, ok := props["down_after"].(float64); ok && val >= 0 {
+		m.downAfter = time.Duration(val) * time.Second
+	}
+}
+
+// parseWatchList parses semicolon-separated matchers of the form
+// "[label=]kind:pattern", e.g.
+//
+//	postgres=name:postgres; gopls=cmdline:gopls\s+serve; redis=pidfile:/run/redis.pid
+//
+// A bare pattern without kind matches by name. Invalid regular expressions
+// are skipped. Repeated labels get a "#n" suffix, since state is kept per
+// label.
+func parseWatchList(s string) []watchMatcher {
+	var out []watchMatcher
+	seen := make(map[string]int)
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		label, spec, hasLabel := strings.Cut(entry, "=")
+		if !hasLabel || strings.Contains(label, ":") {
+			// "=" belongs to the pattern (e.g. a cmdline regex), not a label
+			label, spec = "", entry
+		}
+
+		mt := watchMatcher{kind: watchByName, pattern: spec}
+		if kind, pattern, ok := strings.Cut(spec, ":"); ok {
+			switch kind = strings.TrimSpace(kind); kind {
+			case watchByName, watchByCmdline, watchByPidfile:
+				mt.kind, mt.pattern = kind, pattern
+			}
+		}
+		mt.pattern = strings.TrimSpace(mt.pattern)
+		if mt.pattern == "" {
+			continue
+		}
+		if mt.kind == watchByCmdline {
+			re, err := regexp.Compile(mt.pattern)
+			if err != nil {
+				continue
+			}
+			mt.re = re
+		}
+		mt.label = strings.TrimSpace(label)
+		if mt.label == "" {
+			mt.label = mt.pattern
+		}
+		if seen[mt.label]++; seen[mt.label] > 1 {
+			mt.label = fmt.Sprintf("%s #%d", mt.label, seen[mt.label])
+		}
+		out = append(out, mt)
+	}
+	return out
+}
+
+func (m *WatchdogModule) GetConfigSchema() []protocol.ConfigSchema {
+	return []protocol.ConfigSchema{
+		{
+			Name:  "watch",
+			Label: "Watch (label=name:x; label=cmdline:regex; label=pidfile:/path)",
+			Type:  protocol.ConfigText,
+		},
+		{
+			Name:    "down_after",
+			Label:   "Flag Down After (s, 0 = off)",
+			Type:    protocol.ConfigNumber,
+			Default: 0,
+		},
+	}
+}
+
+func (m *WatchdogModule) GetRenderConfig() protocol.RenderConfig {
+	return protocol.RenderConfig{
+		ID:    "glancehud.core.watchdog",
+		Type:  protocol.TypeKeyValue,
+		Title: "Watchdog",
+		Props: map[string]any{
+			"layout": "column",
+		},
+	}
+}
+
+func (m *WatchdogModule) Update() (*protocol.DataPayload, error) {
+	var procs []watchProc
+	if m.needsProcessList() {
+		var err error
+		if procs, err = listWatchProcs(m.needsCmdline()); err != nil {
+			return nil, err
+		}
+	}
+	return m.evaluate(procs, time.Now()), nil
+}
+
+func (m *WatchdogModule) needsProcessList() bool {
+	for _, mt := range m.matchers {
+		if mt.kind != watchByPidfile {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *WatchdogModule) needsCmdline() bool {
+	for _, mt := range m.matchers {
+		if mt.kind == watchByCmdline {
+			return true
+		}
+	}
+	return false
+}
+
+// evaluate resolves every matcher against procs and updates the per-matcher
+// state. A restart is counted whenever the matched PID differs from the last
+// one seen, whether or not a down sample was observed in between.
+func (m *WatchdogModule) evaluate(procs []watchProc, now time.Time) *protocol.DataPayload {
+	next := make(map[string]*watchState, len(m.matchers))
+	items := make([]protocol.KeyValueItem, 0, len(m.matchers))
+	series := make(map[string]float64, len(m.matchers))
+	var longDown []string
+
+	for _, mt := range m.matchers {
+		st, ok := m.state[mt.label]
+		if !ok {
+			st = &watchState{}
+		}
+		next[mt.label] = st
+
+		p, up := findWatched(mt, procs)
+		if up {
+			if st.pid != 0 && (p.pid != st.pid || p.created != st.created) {
+				st.restarts++
+			}
+			st.pid, st.created, st.downSince = p.pid, p.created, time.Time{}
+			series[mt.label] = 1
+
+			value := fmt.Sprintf("up · pid %d", p.pid)
+			if p.created > 0 {
+				value += " · " + formatDuration(now.Sub(time.UnixMilli(p.created)))
+			}
+			if st.restarts > 0 {
+				value += fmt.Sprintf(" · %d restarts", st.restarts)
+			}
+			items = append(items, protocol.KeyValueItem{Key: mt.label, Value: value, Icon: "CheckCircle"})
+			continue
+		}
+
+		if st.downSince.IsZero() {
+			st.downSince = now
+		}
+		series[mt.label] = 0
+		downFor := now.Sub(st.downSince)
+		if m.downAfter > 0 && downFor >= m.downAfter {
+			longDown = append(longDown, mt.label)
+		}
+		value := "down"
+		if downFor > 0 {
+			value += " · " + formatDuration(downFor)
+		}
+		if st.restarts > 0 {
+			value += fmt.Sprintf(" · %d restarts", st.restarts)
+		}
+		items = append(items, protocol.KeyValueItem{Key: mt.label, Value: value, Icon: "XCircle"})
+	}
+	m.state = next
+
+	if len(items) == 0 {
+		items = []protocol.KeyValueItem{{Key: "Watchdog", Value: "nothing to watch", Icon: "AlertCircle"}}
+	}
+	payload := &protocol.DataPayload{
+		Value:  float64(len(m.matchers) - countDown(series)),
+		Items:  items,
+		Series: series,
+	}
+	if len(longDown) > 0 {
+		// Flag for sidecars, alert rules and the UI: something has been
+		// down longer than down_after
+		payload.Props = map[string]any{
+			"down":         true,
+			"downServices": longDown,
+			"color":        "#ef4444",
+		}
+	}
+	return payload
+}
+
+func countDown(series map[string]float64) int {
+	n := 0
+	for _, v := range series {
+		if v == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// findWatched returns the process a matcher refers to. When several match a
+// name or cmdline, the oldest wins so worker churn under a long-lived parent
+// does not count as restarts.
+func findWatched(mt watchMatcher, procs []watchProc) (watchProc, bool) {
+	if mt.kind == watchByPidfile {
+		return pidfileProc(mt.pattern)
+	}
+	var best watchProc
+	found := false
+	for _, p := range procs {
+		var ok bool
+		switch mt.kind {
+		case watchByCmdline:
+			ok = mt.re.MatchString(p.cmdline)
+		default:
+			ok = strings.EqualFold(p.name, mt.pattern)
+		}
+		if ok && (!found || p.created < best.created || (p.created == best.created && p.pid < best.pid)) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// pidfileProc reads a PID from path and checks that process is alive.
+func pidfileProc(path string) (watchProc, bool) {
+	data, err := os.ReadFile(hostRoot(path))
+	if err != nil {
+		return watchProc{}, false
+	}
+	pid, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 32)
+	if err != nil || pid <= 0 {
+		return watchProc{}, false
+	}
+	ctx := hostContext()
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return watchProc{}, false
+	}
+	wp := watchProc{pid: p.Pid}
+	wp.name, _ = p.NameWithContext(ctx)
+	wp.created, _ = p.CreateTimeWithContext(ctx)
+	return wp, true
+}
+
+func listWatchProcs(withCmdline bool) ([]watchProc, error) {
+	ctx := hostContext()
+	procs, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]watchProc, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		wp := watchProc{pid: p.Pid, name: name}
+		wp.created, _ = p.CreateTimeWithContext(ctx)
+		if withCmdline {
+			wp.cmdline, _ = p.CmdlineWithContext(ctx)
+		}
+		out = append(out, wp)
+	}
+	return out, nil
+}
@@ -0,0 +1,102 @@
+package modules
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"glancehud/internal/protocol"
+)
+
+func TestParseWatchList(t *testing.T) {
+	got := parseWatchList(`db=name:postgres; gopls=cmdline:gopls\s+serve; cmdline:--port=80; redis ; bad=cmdline:(; x=pidfile:/run/x.pid`)
+	want := []struct{ label, kind, pattern string }{
+		{"db", watchByName, "postgres"},
+		{"gopls", watchByCmdline, `gopls\s+serve`},
+		{"--port=80", watchByCmdline, "--port=80"},
+		{"redis", watchByName, "redis"},
+		{"x", watchByPidfile, "/run/x.pid"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("want %d matchers, got %+v", len(want), got)
+	}
+	for i, w := range want {
+		if got[i].label != w.label || got[i].kind != w.kind || got[i].pattern != w.pattern {
+			t.Errorf("[%d] want %+v, got %+v", i, w, got[i])
+		}
+	}
+
+	// State is kept per label, so repeats must not share one
+	got = parseWatchList("web=name:nginx; web=name:caddy; redis; redis")
+	var labels []string
+	for _, mt := range got {
+		labels = append(labels, mt.label)
+	}
+	if strings.Join(labels, ",") != "web,web #2,redis,redis #2" {
+		t.Errorf("want unique labels, got %v", labels)
+	}
+}
+
+func TestWatchdogModule_Evaluate(t *testing.T) {
+	m := NewWatchdogModule()
+	m.ApplyConfig(map[string]interface{}{"watch": "db=name:postgres; ls=cmdline:gopls serve", "down_after": 30.0})
+	t0 := time.Unix(1_700_000_000, 0)
+	started := t0.Add(-3 * time.Hour).UnixMilli()
+
+	procs := []watchProc{
+		{pid: 200, name: "postgres", created: started + 1000}, // worker
+		{pid: 100, name: "postgres", created: started},
+		{pid: 300, name: "go", cmdline: "/usr/bin/gopls serve -rpc.trace", created: started},
+	}
+	p := m.evaluate(procs, t0)
+	items := p.Items.([]protocol.KeyValueItem)
+	if items[0].Value != "up · pid 100 · 3h" || items[0].Icon != "CheckCircle" {
+		t.Errorf("oldest postgres should win, got %+v", items[0])
+	}
+	if p.Value != 2.0 || p.Props != nil {
+		t.Errorf("want 2 up without flag, got value=%v props=%v", p.Value, p.Props)
+	}
+
+	// gopls dies
+	p = m.evaluate(procs[:2], t0.Add(10*time.Second))
+	if items := p.Items.([]protocol.KeyValueItem); items[1].Icon != "XCircle" || p.Series["ls"] != 0 {
+		t.Errorf("want ls down, got %+v", items[1])
+	}
+	if p.Props != nil {
+		t.Error("down flag should wait for down_after")
+	}
+	p = m.evaluate(procs[:2], t0.Add(45*time.Second))
+	if p.Props["down"] != true {
+		t.Errorf("want down flag after 35s, got %v", p.Props)
+	}
+
+	// gopls comes back with a new PID: one restart
+	restarted := watchProc{pid: 400, name: "go", cmdline: "gopls serve", created: t0.Add(50 * time.Second).UnixMilli()}
+	p = m.evaluate(append(procs[:2:2], restarted), t0.Add(time.Minute))
+	items = p.Items.([]protocol.KeyValueItem)
+	if items[1].Value != "up · pid 400 · 1m · 1 restarts" {
+		t.Errorf("unexpected restarted row %q", items[1].Value)
+	}
+	if p.Props != nil {
+		t.Error("flag should clear once everything is up")
+	}
+}
+
+func TestWatchdogModule_Pidfile(t *testing.T) {
+	dir := t.TempDir()
+	pidfile := filepath.Join(dir, "self.pid")
+	if err := os.WriteFile(pidfile, []byte(strconv.Itoa(os.Getpid())+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := findWatched(watchMatcher{kind: watchByPidfile, pattern: pidfile}, nil)
+	if !ok || got.pid != int32(os.Getpid()) {
+		t.Errorf("want own pid from pidfile, got %+v %v", got, ok)
+	}
+	if _, ok := findWatched(watchMatcher{kind: watchByPidfile, pattern: filepath.Join(dir, "missing.pid")}, nil); ok {
+		t.Error("missing pidfile should be down")
+	}
+}
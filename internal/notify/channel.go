@@ -0,0 +1,165 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"glancehud/internal/modules"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"text/template"
+)
+
+// Channel sends one alert event somewhere.
+type Channel interface {
+	Send(ctx context.Context, ev Event) error
+}
+
+// DesktopFunc raises an OS notification. The desktop build wires it to the
+// Wails notification service; headless builds leave it nil.
+type DesktopFunc func(title, body string) error
+
+// ErrDesktopUnavailable is returned by desktop channels when no DesktopFunc
+// is attached (e.g. in headless mode).
+var ErrDesktopUnavailable = errors.New("desktop notifications unavailable")
+
+// --- Desktop ---
+
+type desktopChannel struct {
+	send func() DesktopFunc
+}
+
+func (c *desktopChannel) Send(_ context.Context, ev Event) error {
+	fn := c.send()
+	if fn == nil {
+		return ErrDesktopUnavailable
+	}
+	body := fmt.Sprintf("%s %s = %g (%s %g)", ev.WidgetID, ev.Path, ev.Value, ev.Op, ev.Threshold)
+	return fn(ev.Title(), body)
+}
+
+// --- Webhook ---
+
+type webhookChannel struct {
+	url     string
+	method  string
+	headers map[string]string
+	body    *template.Template // nil: send the event as JSON
+	client  *http.Client
+}
+
+func newWebhookChannel(cfg modules.NotifierConfig) (*webhookChannel, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("url required")
+	}
+	c := &webhookChannel{
+		url:     cfg.URL,
+		method:  cfg.Method,
+		headers: cfg.Headers,
+		client:  &http.Client{},
+	}
+	if c.method == "" {
+		c.method = http.MethodPost
+	}
+	if cfg.Body != "" {
+		t, err := parseTemplate(cfg.Name, cfg.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid body template: %w", err)
+		}
+		c.body = t
+	}
+	return c, nil
+}
+
+func (c *webhookChannel) Send(ctx context.Context, ev Event) error {
+	var payload []byte
+	if c.body != nil {
+		s, err := render(c.body, ev)
+		if err != nil {
+			return fmt.Errorf("render body: %w", err)
+		}
+		payload = []byte(s)
+	} else {
+		var err error
+		if payload, err = json.Marshal(ev); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// --- Command ---
+
+// commandChannel runs a local program. Each argv entry is a template; the
+// event is also passed as JSON on stdin and as GLANCEHUD_ALERT_* env vars.
+type commandChannel struct {
+	argv []*template.Template
+}
+
+func newCommandChannel(cfg modules.NotifierConfig) (*commandChannel, error) {
+	if len(cfg.Command) == 0 {
+		return nil, errors.New("command required")
+	}
+	c := &commandChannel{}
+	for i, arg := range cfg.Command {
+		t, err := parseTemplate(fmt.Sprintf("%s[%d]", cfg.Name, i), arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command template: %w", err)
+		}
+		c.argv = append(c.argv, t)
+	}
+	return c, nil
+}
+
+func (c *commandChannel) Send(ctx context.Context, ev Event) error {
+	args := make([]string, len(c.argv))
+	for i, t := range c.argv {
+		s, err := render(t, ev)
+		if err != nil {
+			return fmt.Errorf("render argv[%d]: %w", i, err)
+		}
+		args[i] = s
+	}
+	stdin, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Env = append(os.Environ(),
+		"GLANCEHUD_ALERT_KIND="+ev.Kind,
+		"GLANCEHUD_ALERT_NAME="+ev.Name,
+		"GLANCEHUD_ALERT_WIDGET="+ev.WidgetID,
+		"GLANCEHUD_ALERT_SEVERITY="+ev.Severity,
+		"GLANCEHUD_ALERT_VALUE="+strconv.FormatFloat(ev.Value, 'g', -1, 64),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"glancehud/internal/modules"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds a single send when the channel sets no timeout.
+const DefaultTimeout = 10 * time.Second
+
+type entry struct {
+	ch          Channel
+	fingerprint string
+	minInterval time.Duration
+	timeout     time.Duration
+	lastSent    time.Time
+}
+
+// Dispatcher routes alert events to named channels, applying per-channel rate
+// limits and a global snooze. Sends run in their own goroutines so a slow
+// webhook never stalls a monitor loop. It is safe for concurrent use.
+type Dispatcher struct {
+	mu          sync.Mutex
+	channels    map[string]*entry
+	snoozeUntil time.Time
+	desktop     DesktopFunc
+	wg          sync.WaitGroup
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{channels: make(map[string]*entry)}
+}
+
+// SetDesktop attaches (or with nil, detaches) the OS notification backend.
+func (d *Dispatcher) SetDesktop(fn DesktopFunc) {
+	d.mu.Lock()
+	d.desktop = fn
+	d.mu.Unlock()
+}
+
+func (d *Dispatcher) desktopFunc() DesktopFunc {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.desktop
+}
+
+// SetChannels replaces the configured channels. Invalid entries are skipped
+// and reported. Rate-limit state survives for channels whose config did not
+// change, so saving settings cannot be used to bypass the limit.
+func (d *Dispatcher) SetChannels(cfgs []modules.NotifierConfig) []error {
+	var errs []error
+	next := make(map[string]*entry, len(cfgs))
+	for i, cfg := range cfgs {
+		e, err := d.build(cfg)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("notifier %d (%q): %w", i, cfg.Name, err))
+			continue
+		}
+		if _, dup := next[cfg.Name]; dup {
+			errs = append(errs, fmt.Errorf("notifier %d: duplicate name %q", i, cfg.Name))
+			continue
+		}
+		next[cfg.Name] = e
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for name, e := range next {
+		if old, ok := d.channels[name]; ok && old.fingerprint == e.fingerprint {
+			e.lastSent = old.lastSent
+		}
+	}
+	d.channels = next
+	return errs
+}
+
+func (d *Dispatcher) build(cfg modules.NotifierConfig) (*entry, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("name required")
+	}
+	e := &entry{timeout: DefaultTimeout, fingerprint: fmt.Sprintf("%+v", cfg)}
+	var err error
+	if cfg.RateLimit != "" {
+		if e.minInterval, err = time.ParseDuration(cfg.RateLimit); err != nil || e.minInterval < 0 {
+			return nil, fmt.Errorf("invalid rateLimit %q", cfg.RateLimit)
+		}
+	}
+	if cfg.Timeout != "" {
+		if e.timeout, err = time.ParseDuration(cfg.Timeout); err != nil || e.timeout <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", cfg.Timeout)
+		}
+	}
+
+	switch cfg.Type {
+	case "desktop":
+		e.ch = &desktopChannel{send: d.desktopFunc}
+	case "webhook":
+		e.ch, err = newWebhookChannel(cfg)
+	case "command":
+		e.ch, err = newCommandChannel(cfg)
+	default:
+		err = fmt.Errorf("unknown type %q", cfg.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Snooze suppresses all notifications until the given time. A zero time
+// resumes immediately. Alerts are still evaluated and emitted as events.
+func (d *Dispatcher) Snooze(until time.Time) {
+	d.mu.Lock()
+	d.snoozeUntil = until
+	d.mu.Unlock()
+}
+
+// SnoozedUntil returns the end of the active snooze, or the zero time.
+func (d *Dispatcher) SnoozedUntil() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if time.Now().After(d.snoozeUntil) {
+		return time.Time{}
+	}
+	return d.snoozeUntil
+}
+
+// Dispatch sends ev to each named channel asynchronously. Unknown names,
+// snoozed periods and rate-limited channels are logged and skipped.
+func (d *Dispatcher) Dispatch(names []string, ev Event) {
+	now := time.UnixMilli(ev.Time)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if now.Before(d.snoozeUntil) {
+		slog.Debug("Notification snoozed", "alert", ev.Name, "until", d.snoozeUntil)
+		return
+	}
+	for _, name := range names {
+		e, ok := d.channels[name]
+		if !ok {
+			slog.Warn("Unknown notifier", "name", name, "alert", ev.Name)
+			continue
+		}
+		if e.minInterval > 0 && !e.lastSent.IsZero() && now.Sub(e.lastSent) < e.minInterval {
+			slog.Info("Notification rate limited", "notifier", name, "alert", ev.Name)
+			continue
+		}
+		e.lastSent = now
+
+		d.wg.Add(1)
+		go func(name string, ch Channel, timeout time.Duration) {
+			defer d.wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			if err := ch.Send(ctx, ev); err != nil {
+				slog.Error("Notification failed", "notifier", name, "alert", ev.Name, "error", err)
+			}
+		}(name, e.ch, e.timeout)
+	}
+}
+
+// wait blocks until in-flight sends finish. Used by tests.
+func (d *Dispatcher) wait() {
+	d.wg.Wait()
+}
@@ -0,0 +1,262 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookStandIn records request bodies and headers.
+type webhookStandIn struct {
+	mu      sync.Mutex
+	bodies  []string
+	headers []http.Header
+}
+
+func newWebhookStandIn(t *testing.T) (*webhookStandIn, *httptest.Server) {
+	t.Helper()
+	w := &webhookStandIn{}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.mu.Lock()
+		w.bodies = append(w.bodies, string(b))
+		w.headers = append(w.headers, r.Header.Clone())
+		w.mu.Unlock()
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return w, srv
+}
+
+func (w *webhookStandIn) count() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.bodies)
+}
+
+func testEvent(at time.Time) Event {
+	v := 97.5
+	return NewEvent(KindFire, protocol.AlertStatus{
+		Name:      "cpu-hot",
+		WidgetID:  "glancehud.core.cpu",
+		Path:      "value",
+		Op:        ">",
+		Threshold: 90,
+		Severity:  "critical",
+		Value:     &v,
+	}, at)
+}
+
+// --- Webhook ---
+
+func TestWebhook_TemplatedBody(t *testing.T) {
+	hook, srv := newWebhookStandIn(t)
+	d := NewDispatcher()
+	errs := d.SetChannels([]modules.NotifierConfig{{
+		Name:    "chat",
+		Type:    "webhook",
+		URL:     srv.URL,
+		Headers: map[string]string{"Authorization": "Bearer t0k"},
+		Body:    `{"text": {{json (printf "%s %s=%.1f" .Name .WidgetID .Value)}}, "kind": "{{.Kind}}"}`,
+	}})
+	if len(errs) != 0 {
+		t.Fatalf("SetChannels: %v", errs)
+	}
+
+	d.Dispatch([]string{"chat"}, testEvent(time.Now()))
+	d.wait()
+
+	if hook.count() != 1 {
+		t.Fatalf("want 1 request, got %d", hook.count())
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(hook.bodies[0]), &got); err != nil {
+		t.Fatalf("body is not JSON: %v (%s)", err, hook.bodies[0])
+	}
+	if got["text"] != "cpu-hot glancehud.core.cpu=97.5" || got["kind"] != "fire" {
+		t.Errorf("unexpected body: %v", got)
+	}
+	if h := hook.headers[0].Get("Authorization"); h != "Bearer t0k" {
+		t.Errorf("want Authorization header, got %q", h)
+	}
+}
+
+func TestWebhook_DefaultBodyIsEventJSON(t *testing.T) {
+	hook, srv := newWebhookStandIn(t)
+	d := NewDispatcher()
+	d.SetChannels([]modules.NotifierConfig{{Name: "raw", Type: "webhook", URL: srv.URL}})
+
+	d.Dispatch([]string{"raw"}, testEvent(time.Now()))
+	d.wait()
+
+	var ev Event
+	if err := json.Unmarshal([]byte(hook.bodies[0]), &ev); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if ev.Name != "cpu-hot" || ev.Value != 97.5 || ev.Severity != "critical" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestWebhook_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	c, err := newWebhookChannel(modules.NotifierConfig{Name: "x", URL: srv.URL})
+	if err != nil {
+		t.Fatalf("newWebhookChannel: %v", err)
+	}
+	if err := c.Send(t.Context(), testEvent(time.Now())); err == nil {
+		t.Error("want error for 500 response")
+	}
+}
+
+// --- Rate limiting & snooze ---
+
+func TestDispatch_RateLimitPerChannel(t *testing.T) {
+	limited, srvA := newWebhookStandIn(t)
+	free, srvB := newWebhookStandIn(t)
+	d := NewDispatcher()
+	d.SetChannels([]modules.NotifierConfig{
+		{Name: "a", Type: "webhook", URL: srvA.URL, RateLimit: "1m"},
+		{Name: "b", Type: "webhook", URL: srvB.URL},
+	})
+
+	t0 := time.Unix(1000, 0)
+	d.Dispatch([]string{"a", "b"}, testEvent(t0))
+	d.Dispatch([]string{"a", "b"}, testEvent(t0.Add(30*time.Second)))
+	d.Dispatch([]string{"a", "b"}, testEvent(t0.Add(61*time.Second)))
+	d.wait()
+
+	if n := limited.count(); n != 2 {
+		t.Errorf("rate limited channel: want 2 sends, got %d", n)
+	}
+	if n := free.count(); n != 3 {
+		t.Errorf("unlimited channel: want 3 sends, got %d", n)
+	}
+}
+
+func TestSetChannels_KeepsRateLimitState(t *testing.T) {
+	hook, srv := newWebhookStandIn(t)
+	d := NewDispatcher()
+	cfg := []modules.NotifierConfig{{Name: "a", Type: "webhook", URL: srv.URL, RateLimit: "1m"}}
+	d.SetChannels(cfg)
+
+	t0 := time.Unix(1000, 0)
+	d.Dispatch([]string{"a"}, testEvent(t0))
+	d.SetChannels(cfg)
+	d.Dispatch([]string{"a"}, testEvent(t0.Add(time.Second)))
+	d.wait()
+
+	if n := hook.count(); n != 1 {
+		t.Errorf("want 1 send after re-applying config, got %d", n)
+	}
+}
+
+func TestDispatch_Snooze(t *testing.T) {
+	hook, srv := newWebhookStandIn(t)
+	d := NewDispatcher()
+	d.SetChannels([]modules.NotifierConfig{{Name: "a", Type: "webhook", URL: srv.URL}})
+
+	now := time.Now()
+	d.Snooze(now.Add(time.Hour))
+	if d.SnoozedUntil().IsZero() {
+		t.Error("want active snooze")
+	}
+	d.Dispatch([]string{"a"}, testEvent(now))
+	d.wait()
+	if n := hook.count(); n != 0 {
+		t.Errorf("want no sends while snoozed, got %d", n)
+	}
+
+	d.Snooze(time.Time{})
+	d.Dispatch([]string{"a"}, testEvent(now))
+	d.wait()
+	if n := hook.count(); n != 1 {
+		t.Errorf("want 1 send after resume, got %d", n)
+	}
+}
+
+// --- Desktop & command ---
+
+func TestDesktop_UsesAttachedFunc(t *testing.T) {
+	d := NewDispatcher()
+	d.SetChannels([]modules.NotifierConfig{{Name: "os", Type: "desktop"}})
+
+	var mu sync.Mutex
+	var titles []string
+	d.SetDesktop(func(title, _ string) error {
+		mu.Lock()
+		titles = append(titles, title)
+		mu.Unlock()
+		return nil
+	})
+	d.Dispatch([]string{"os"}, testEvent(time.Now()))
+	d.wait()
+
+	if len(titles) != 1 || titles[0] != "CRITICAL: cpu-hot" {
+		t.Errorf("unexpected titles: %v", titles)
+	}
+}
+
+func TestDesktop_UnavailableWithoutFunc(t *testing.T) {
+	c := &desktopChannel{send: func() DesktopFunc { return nil }}
+	if err := c.Send(t.Context(), testEvent(time.Now())); !errors.Is(err, ErrDesktopUnavailable) {
+		t.Errorf("want ErrDesktopUnavailable, got %v", err)
+	}
+}
+
+func TestCommand_RunsWithTemplatedArgs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	out := filepath.Join(t.TempDir(), "out")
+	c, err := newCommandChannel(modules.NotifierConfig{
+		Name:    "log",
+		Command: []string{"sh", "-c", `echo "$1 $GLANCEHUD_ALERT_VALUE" > ` + out, "sh", "{{.Name}}"},
+	})
+	if err != nil {
+		t.Fatalf("newCommandChannel: %v", err)
+	}
+	if err := c.Send(t.Context(), testEvent(time.Now())); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	b, _ := os.ReadFile(out)
+	if got := strings.TrimSpace(string(b)); got != "cpu-hot 97.5" {
+		t.Errorf("want %q, got %q", "cpu-hot 97.5", got)
+	}
+}
+
+// --- Config validation ---
+
+func TestSetChannels_InvalidSkipped(t *testing.T) {
+	d := NewDispatcher()
+	errs := d.SetChannels([]modules.NotifierConfig{
+		{Name: "", Type: "desktop"},
+		{Name: "a", Type: "pager"},
+		{Name: "b", Type: "webhook"},
+		{Name: "c", Type: "webhook", URL: "http://x", Body: "{{.Nope"},
+		{Name: "d", Type: "command"},
+		{Name: "e", Type: "desktop", RateLimit: "often"},
+		{Name: "ok", Type: "desktop"},
+		{Name: "ok", Type: "desktop"},
+	})
+	if len(errs) != 7 {
+		t.Errorf("want 7 errors, got %d: %v", len(errs), errs)
+	}
+	if len(d.channels) != 1 {
+		t.Errorf("want 1 valid channel, got %d", len(d.channels))
+	}
+}
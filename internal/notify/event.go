@@ -0,0 +1,78 @@
+// Package notify delivers alert transitions to external channels: OS
+// notifications, HTTP webhooks and local commands.
+package notify
+
+import (
+	"encoding/json"
+	"glancehud/internal/protocol"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Event kinds.
+const (
+	KindFire    = "fire"
+	KindResolve = "resolve"
+)
+
+// Event is the data passed to channel templates and serialised as the default
+// webhook body. Fields are flat so templates can say {{.Name}} and {{.Value}}.
+type Event struct {
+	Kind      string  `json:"kind"` // "fire" | "resolve"
+	Name      string  `json:"name"`
+	WidgetID  string  `json:"widgetId"`
+	Path      string  `json:"path"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+	Value     float64 `json:"value"`
+	Severity  string  `json:"severity"`
+	Time      int64   `json:"time"` // Unix milliseconds
+}
+
+// NewEvent builds an Event from an alert status.
+func NewEvent(kind string, st protocol.AlertStatus, now time.Time) Event {
+	ev := Event{
+		Kind:      kind,
+		Name:      st.Name,
+		WidgetID:  st.WidgetID,
+		Path:      st.Path,
+		Op:        st.Op,
+		Threshold: st.Threshold,
+		Severity:  st.Severity,
+		Time:      now.UnixMilli(),
+	}
+	if st.Value != nil {
+		ev.Value = *st.Value
+	}
+	return ev
+}
+
+// Title is a short human-readable headline, used for desktop notifications.
+func (e Event) Title() string {
+	if e.Kind == KindResolve {
+		return "Resolved: " + e.Name
+	}
+	return strings.ToUpper(e.Severity) + ": " + e.Name
+}
+
+// templateFuncs are available in Body and Command templates. json quotes a
+// value so string fields can be embedded safely in a JSON body.
+var templateFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+}
+
+func render(t *template.Template, ev Event) (string, error) {
+	var sb strings.Builder
+	if err := t.Execute(&sb, ev); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,281 @@
+// Package prom reads the Prometheus text exposition format (and the subset of
+// OpenMetrics that shares its sample syntax) and selects series from it.
+package prom
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sample is one line of an exposition: a metric name, its labels and value.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Key identifies the series a sample belongs to, e.g.
+// `http_requests_total{code="200",job="api"}`. Labels are sorted.
+func (s Sample) Key() string {
+	names := make([]string, 0, len(s.Labels))
+	for n := range s.Labels {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(s.Name)
+	b.WriteByte('{')
+	for i, n := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=%q", n, s.Labels[n])
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+// Parse reads every sample from r. Comments, HELP/TYPE metadata, timestamps
+// and OpenMetrics exemplars are skipped; parsing stops at "# EOF".
+func Parse(r io.Reader) ([]Sample, error) {
+	var out []Sample
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "# EOF" {
+			break
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		s, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		out = append(out, s)
+	}
+	return out, sc.Err()
+}
+
+// parseSample parses `name{l="v",...} value [timestamp] [# exemplar]`.
+func parseSample(line string) (Sample, error) {
+	name, rest := splitName(line)
+	if name == "" {
+		return Sample{}, errors.New("missing metric name")
+	}
+	s := Sample{Name: name, Labels: map[string]string{}}
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		if s.Labels, rest, err = parseLabels(rest); err != nil {
+			return Sample{}, err
+		}
+	}
+	if i := strings.Index(rest, " # "); i >= 0 {
+		rest = rest[:i] // exemplar
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return Sample{}, fmt.Errorf("malformed sample %q", line)
+	}
+	v, err := parseValue(fields[0])
+	if err != nil {
+		return Sample{}, err
+	}
+	s.Value = v
+	return s, nil
+}
+
+// splitName splits off a leading metric or label name.
+func splitName(s string) (name, rest string) {
+	i := 0
+	for i < len(s) && isNameChar(s[i], i == 0) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func isNameChar(c byte, first bool) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c == '_', c == ':':
+		return true
+	case c >= '0' && c <= '9':
+		return !first
+	}
+	return false
+}
+
+// parseLabels parses `{name="value",...}` at the start of s and returns the
+// remainder after the closing brace.
+func parseLabels(s string) (map[string]string, string, error) {
+	labels := map[string]string{}
+	s = strings.TrimPrefix(s, "{")
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if strings.HasPrefix(s, "}") {
+			return labels, s[1:], nil
+		}
+		name, rest := splitName(s)
+		if name == "" {
+			return nil, "", fmt.Errorf("bad label name in %q", s)
+		}
+		rest = strings.TrimLeft(rest, " ")
+		if !strings.HasPrefix(rest, "=") {
+			return nil, "", fmt.Errorf("label %s: missing '='", name)
+		}
+		value, rest, err := parseQuoted(strings.TrimLeft(rest[1:], " "))
+		if err != nil {
+			return nil, "", fmt.Errorf("label %s: %w", name, err)
+		}
+		labels[name] = value
+		s = rest
+	}
+}
+
+// parseQuoted reads a double-quoted label value with \\, \" and \n escapes.
+func parseQuoted(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", errors.New("value not quoted")
+	}
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return b.String(), s[i+1:], nil
+		case '\\':
+			if i+1 == len(s) {
+				return "", "", errors.New("unterminated escape")
+			}
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", "", errors.New("unterminated value")
+}
+
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("bad value %q", s)
+	}
+	return v, nil
+}
+
+// Selector matches samples by metric name and label matchers, written as in
+// PromQL: `http_requests_total{job="api",code=~"5.."}`.
+type Selector struct {
+	Name     string
+	Matchers []Matcher
+}
+
+// Matcher is one label condition. Op is "=", "!=", "=~" or "!~"; regular
+// expressions are anchored, as in Prometheus.
+type Matcher struct {
+	Label string
+	Op    string
+	Value string
+	re    *regexp.Regexp
+}
+
+// ParseSelector parses a PromQL-style series selector.
+func ParseSelector(s string) (*Selector, error) {
+	s = strings.TrimSpace(s)
+	name, rest := splitName(s)
+	if name == "" {
+		return nil, fmt.Errorf("selector %q: missing metric name", s)
+	}
+	sel := &Selector{Name: name}
+	rest = strings.TrimSpace(rest)
+	if rest == "" {
+		return sel, nil
+	}
+	if !strings.HasPrefix(rest, "{") {
+		return nil, fmt.Errorf("selector %q: unexpected %q", s, rest)
+	}
+	rest = rest[1:]
+	for {
+		rest = strings.TrimLeft(rest, " ,")
+		if rest == "}" {
+			return sel, nil
+		}
+		label, r := splitName(rest)
+		if label == "" {
+			return nil, fmt.Errorf("selector %q: bad label name", s)
+		}
+		r = strings.TrimLeft(r, " ")
+		var m Matcher
+		for _, op := range []string{"=~", "!~", "!=", "="} {
+			if strings.HasPrefix(r, op) {
+				m = Matcher{Label: label, Op: op}
+				r = strings.TrimLeft(r[len(op):], " ")
+				break
+			}
+		}
+		if m.Op == "" {
+			return nil, fmt.Errorf("selector %q: label %s: missing operator", s, label)
+		}
+		value, r, err := parseQuoted(r)
+		if err != nil {
+			return nil, fmt.Errorf("selector %q: label %s: %w", s, label, err)
+		}
+		m.Value = value
+		if m.Op == "=~" || m.Op == "!~" {
+			if m.re, err = regexp.Compile("^(?:" + value + ")$"); err != nil {
+				return nil, fmt.Errorf("selector %q: label %s: %w", s, label, err)
+			}
+		}
+		sel.Matchers = append(sel.Matchers, m)
+		rest = r
+		if rest == "" {
+			return nil, fmt.Errorf("selector %q: missing '}'", s)
+		}
+	}
+}
+
+// Match reports whether s belongs to the selected series. A missing label
+// counts as the empty string.
+func (sel *Selector) Match(s Sample) bool {
+	if s.Name != sel.Name {
+		return false
+	}
+	for _, m := range sel.Matchers {
+		v := s.Labels[m.Label]
+		var ok bool
+		switch m.Op {
+		case "=":
+			ok = v == m.Value
+		case "!=":
+			ok = v != m.Value
+		case "=~":
+			ok = m.re.MatchString(v)
+		case "!~":
+			ok = !m.re.MatchString(v)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,91 @@
+package prom
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+const exposition = `# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{job="api",code="200"} 1027 1395066363000
+http_requests_total{job="api",code="503"} 3
+http_requests_total{ job="web" , code="200", path="C:\\dir \"x\"\n"} 12.5e1
+queue_depth 42
+go_gc_duration_seconds{quantile="0.5"} NaN
+latency_bucket{le="+Inf"} 7 # {trace_id="abc"} 0.5
+# EOF
+ignored_after_eof 1
+`
+
+func TestParse(t *testing.T) {
+	samples, err := Parse(strings.NewReader(exposition))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 6 {
+		t.Fatalf("want 6 samples, got %d: %+v", len(samples), samples)
+	}
+	if s := samples[0]; s.Name != "http_requests_total" || s.Labels["code"] != "200" || s.Value != 1027 {
+		t.Errorf("timestamped sample: %+v", s)
+	}
+	if s := samples[2]; s.Labels["path"] != "C:\\dir \"x\"\n" || s.Value != 125 {
+		t.Errorf("escaped labels: %+v", s)
+	}
+	if s := samples[3]; s.Name != "queue_depth" || len(s.Labels) != 0 || s.Value != 42 {
+		t.Errorf("bare sample: %+v", s)
+	}
+	if !math.IsNaN(samples[4].Value) {
+		t.Errorf("want NaN, got %v", samples[4].Value)
+	}
+	if s := samples[5]; s.Labels["le"] != "+Inf" || s.Value != 7 {
+		t.Errorf("exemplar sample: %+v", s)
+	}
+	if got := samples[0].Key(); got != `http_requests_total{code="200",job="api"}` {
+		t.Errorf("key: %s", got)
+	}
+
+	for _, bad := range []string{`x{a="1" 2`, `x{a=1} 2`, `x 1 2 3`, `x abc`, `{a="1"} 2`} {
+		if _, err := Parse(strings.NewReader(bad)); err == nil {
+			t.Errorf("want error for %q", bad)
+		}
+	}
+}
+
+func TestSelector(t *testing.T) {
+	samples, _ := Parse(strings.NewReader(exposition))
+
+	tests := []struct {
+		sel  string
+		want int
+	}{
+		{"http_requests_total", 3},
+		{`http_requests_total{job="api"}`, 2},
+		{`http_requests_total{code=~"5.."}`, 1},
+		{`http_requests_total{code!~"2.*", job="api"}`, 1},
+		{`http_requests_total{path!=""}`, 1},
+		{`http_requests_total{code=~"20"}`, 0}, // anchored
+		{`queue_depth{}`, 1},
+	}
+	for _, tt := range tests {
+		sel, err := ParseSelector(tt.sel)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sel, err)
+		}
+		n := 0
+		for _, s := range samples {
+			if sel.Match(s) {
+				n++
+			}
+		}
+		if n != tt.want {
+			t.Errorf("%s: want %d matches, got %d", tt.sel, tt.want, n)
+		}
+	}
+
+	for _, bad := range []string{"", `{job="x"}`, `x{job}`, `x{job="a"`, `x{job=~"("}`, `x y`} {
+		if _, err := ParseSelector(bad); err == nil {
+			t.Errorf("want error for %q", bad)
+		}
+	}
+}
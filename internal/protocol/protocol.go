@@ -30,6 +30,9 @@ type DataPayload struct {
 	Label        string `json:"label,omitempty"`        // Gauge 中心文字
 	DisplayValue string `json:"displayValue,omitempty"` // Sparkline 旁顯示文字
 
+	// 多序列 Sparkline：每個序列的當前值，key 對應 RenderConfig.Props["series"][].key
+	Series map[string]float64 `json:"series,omitempty"`
+
 	// 列表類數據 (BarList, KeyValue)
 	Items any `json:"items,omitempty"` // []BarItem 或 []KeyValueItem
 
@@ -49,7 +52,8 @@ type UpdateEvent struct {
 type BarListItem struct {
 	Label   string  `json:"label"`
 	Percent float64 `json:"percent"`
-	Value   string  `json:"value"` // e.g. "100GB / 500GB"
+	Value   string  `json:"value"`           // e.g. "100GB / 500GB"
+	Color   string  `json:"color,omitempty"` // 覆蓋此列的進度條顏色 (預設依 percent 自動配色)
 }
 
 // KeyValueItem 用於 KeyValue List
@@ -103,9 +107,36 @@ type SidecarRequest struct {
 
 // SidecarResponse 對應 POST /api/widget 的 Response
 // Props 包含使用者在 Settings 中設定的值，供 sidecar 讀回
+// 透過 WebSocket 推送時，ModuleID 標示此 Props 屬於哪個 widget (同一連線可多工)
+// Actions 是使用者在 Settings 按下、尚未交付給 sidecar 的 ConfigButton action
 type SidecarResponse struct {
-	Status string         `json:"status"`
-	Props  map[string]any `json:"props,omitempty"`
+	Status   string         `json:"status"`
+	ModuleID string         `json:"module_id,omitempty"`
+	Props    map[string]any `json:"props,omitempty"`
+	Actions  []string       `json:"actions,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// WriteResponse 對應 POST /api/write 的 Response
+// Written 為本次更新的 widget 數；Props 以 widget ID 為 key，帶回使用者在 Settings 中設定的值
+// Errors 列出無法解析或沒有對應規則的行，其餘行照常寫入
+type WriteResponse struct {
+	Status  string                    `json:"status"` // "ok" | "partial" | "error"
+	Written int                       `json:"written"`
+	Props   map[string]map[string]any `json:"props,omitempty"`
+	Errors  []LineError               `json:"errors,omitempty"`
+}
+
+// LineError 是 POST /api/write 中單行的錯誤，Line 從 1 起算
+type LineError struct {
+	Line  int    `json:"line"`
+	Error string `json:"error"`
+}
+
+// ActionRequest 對應 POST /api/action 的 Body (觸發 ConfigButton action)
+type ActionRequest struct {
+	ModuleID string `json:"module_id"`
+	Action   string `json:"action"`
 }
 
 // StatEntry 是單一 widget 的當前狀態快照，用於 GET /api/stats
@@ -121,3 +152,44 @@ type StatEntry struct {
 type StatsResponse struct {
 	Widgets map[string]StatEntry `json:"widgets"`
 }
+
+// HistoryPoint 是單一歷史取樣，T 為 Unix 毫秒
+type HistoryPoint struct {
+	T int64   `json:"t"`
+	V float64 `json:"v"`
+}
+
+// HistoryResponse 是 GET /api/history 的回應結構
+type HistoryResponse struct {
+	ID     string         `json:"id"`
+	Points []HistoryPoint `json:"points"`
+}
+
+// AlertState 是告警規則的目前狀態
+type AlertState string
+
+const (
+	AlertOK      AlertState = "ok"
+	AlertPending AlertState = "pending" // 條件成立，但尚未達到 for 持續時間
+	AlertFiring  AlertState = "firing"
+)
+
+// AlertStatus 描述單一告警規則的狀態，用於 alert:fire / alert:resolve 事件與 GET /api/alerts
+type AlertStatus struct {
+	Name      string     `json:"name"`
+	WidgetID  string     `json:"widgetId"` // Render ID
+	Path      string     `json:"path"`
+	Op        string     `json:"op"`
+	Threshold float64    `json:"threshold"`
+	Severity  string     `json:"severity"`
+	State     AlertState `json:"state"`
+	Value     *float64   `json:"value,omitempty"`  // 最後一次評估的數值
+	Since     int64      `json:"since,omitempty"`  // 進入目前狀態的時間 (Unix 毫秒)
+	Notify    []string   `json:"notify,omitempty"` // 觸發/解除時通知的 Notifier 名稱
+}
+
+// AlertsResponse 是 GET /api/alerts 的回應結構
+type AlertsResponse struct {
+	Alerts       []AlertStatus `json:"alerts"`
+	SnoozedUntil int64         `json:"snoozedUntil,omitempty"` // 通知暫停至此時間 (Unix 毫秒)；0 表示未暫停
+}
@@ -1,17 +1,34 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"glancehud/internal/influx"
 	"glancehud/internal/protocol"
 	"log/slog"
+	"mime"
+	"net"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"time"
 
-	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/coder/websocket"
 )
 
+// StreamKeepalive is how often /api/stream writes an SSE comment line so that
+// proxies and clients do not consider an idle connection dead.
+const StreamKeepalive = 15 * time.Second
+
+// sidecarSocketReadLimit caps a single WebSocket frame from a sidecar.
+const sidecarSocketReadLimit = 1 << 20
+
+// maxWriteBody caps a POST /api/write request body.
+const maxWriteBody = 4 << 20
+
 type APIService struct {
-	app           *application.App
 	systemService *SystemService
 }
 
@@ -21,22 +38,41 @@ func NewAPIService(s *SystemService) *APIService {
 	}
 }
 
-func (s *APIService) Start(app *application.App) {
-	s.app = app
+// Start launches the HTTP API server, and the StatsD listener when
+// configured, in the background.
+func (s *APIService) Start() {
 	go s.startHTTPServer()
+	go s.startStatsD()
+}
+
+// apiHost is the interface the API binds to: GLANCEHUD_HOST, or loopback
+// only by default. Headless deployments set it to 0.0.0.0 to expose the API
+// remotely.
+func apiHost() string {
+	if host := os.Getenv("GLANCEHUD_HOST"); host != "" {
+		return host
+	}
+	return "127.0.0.1"
 }
 
 func (s *APIService) startHTTPServer() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/api/widget", s.handleWidgetPush)
+	mux.HandleFunc("/api/widget/ws", s.handleWidgetSocket)
 	mux.HandleFunc("/api/stats", s.handleStatsPull)
+	mux.HandleFunc("/api/action", s.handleAction)
+	mux.HandleFunc("/api/history", s.handleHistory)
+	mux.HandleFunc("/metrics", s.handleMetrics)
+	mux.HandleFunc("/api/alerts", s.handleAlerts)
+	mux.HandleFunc("/api/stream", s.handleStream)
+	mux.HandleFunc("/api/write", s.handleWrite)
 
 	// Allow port override via GLANCEHUD_PORT env var (default: 9090)
 	port := os.Getenv("GLANCEHUD_PORT")
 	if port == "" {
 		port = "9090"
 	}
-	addr := "127.0.0.1:" + port
+	addr := net.JoinHostPort(apiHost(), port)
 	slog.Info("API server listening", "addr", addr)
 
 	if err := http.ListenAndServe(addr, mux); err != nil {
@@ -44,6 +80,31 @@ func (s *APIService) startHTTPServer() {
 	}
 }
 
+// startStatsD binds the StatsD UDP listener next to the HTTP server when
+// AppConfig.StatsD is set. The listener lives for the rest of the process:
+// like the HTTP port, its config is read once and a change needs a restart.
+func (s *APIService) startStatsD() {
+	cfg := s.systemService.GetConfig().StatsD
+	if cfg == nil {
+		return
+	}
+	l, err := NewStatsDListener(s.systemService, *cfg)
+	if err != nil {
+		slog.Error("StatsD listener disabled", "error", err)
+		return
+	}
+	addr := net.JoinHostPort(apiHost(), strconv.Itoa(l.port))
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		slog.Error("StatsD listener failed to start", "error", err)
+		return
+	}
+	slog.Info("StatsD listener listening", "addr", addr, "flush", l.flush)
+
+	go l.runFlush()
+	l.Serve(conn)
+}
+
 func (s *APIService) handleWidgetPush(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -74,13 +135,215 @@ func (s *APIService) handleWidgetPush(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	if err := json.NewEncoder(w).Encode(protocol.SidecarResponse{
-		Status: "ok",
-		Props:  currentProps,
+		Status:  "ok",
+		Props:   currentProps,
+		Actions: s.systemService.takeSidecarActions(req.ModuleID),
 	}); err != nil {
 		slog.Error("Failed to encode response", "moduleId", req.ModuleID, "error", err)
 	}
 }
 
+// handleWidgetSocket upgrades to a WebSocket on which a sidecar sends
+// SidecarRequest frames over one long-lived connection. The server answers with
+// SidecarResponse frames: once when a module ID is first seen on the socket, and
+// again whenever the user changes that widget's settings. Closing the socket
+// marks every module it carried offline immediately.
+func (s *APIService) handleWidgetSocket(w http.ResponseWriter, r *http.Request) {
+	c, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		slog.Error("Sidecar socket upgrade failed", "error", err)
+		return
+	}
+	defer c.CloseNow()
+	c.SetReadLimit(sidecarSocketReadLimit)
+
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	conn := newSidecarConn()
+	defer s.systemService.detachSidecarConn(conn)
+
+	// Writer: the only goroutine that sends data frames on c.
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resp := <-conn.out:
+				data, err := json.Marshal(resp)
+				if err != nil {
+					slog.Error("Failed to encode socket response", "moduleId", resp.ModuleID, "error", err)
+					continue
+				}
+				if err := c.Write(ctx, websocket.MessageText, data); err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	// Heartbeat: a peer that stops answering pings is treated as disconnected.
+	go func() {
+		ticker := time.NewTicker(SidecarTTL / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				pingCtx, pingCancel := context.WithTimeout(ctx, SidecarTTL/2)
+				err := c.Ping(pingCtx)
+				pingCancel()
+				if err != nil {
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_, data, err := c.Read(ctx)
+		if err != nil {
+			return
+		}
+
+		var req protocol.SidecarRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			conn.send(protocol.SidecarResponse{Status: "error", Error: "Invalid JSON"})
+			continue
+		}
+		if req.ModuleID == "" {
+			conn.send(protocol.SidecarResponse{Status: "error", Error: "module_id required"})
+			continue
+		}
+
+		s.systemService.RegisterSidecar(req.ModuleID, req.Template, req.Schema)
+		props, attached := s.systemService.attachSidecarConn(req.ModuleID, conn)
+		if req.Data != nil {
+			props = s.systemService.UpdateSidecarData(req.ModuleID, req.Data)
+		}
+
+		// Later settings changes and actions arrive via SidecarSource pushes.
+		if attached {
+			conn.send(protocol.SidecarResponse{
+				Status:   "ok",
+				ModuleID: req.ModuleID,
+				Props:    props,
+				Actions:  s.systemService.takeSidecarActions(req.ModuleID),
+			})
+		}
+	}
+}
+
+// handleWrite ingests InfluxDB line protocol. Points are mapped to widgets by
+// the AppConfig.Influx rules; malformed or unmapped lines are reported one by
+// one while the rest are still written.
+func (s *APIService) handleWrite(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if !requireContentType(w, r, "text/plain") {
+		return
+	}
+	// text/plain needs no CORS preflight, so refuse cross-site browser
+	// requests outright.
+	if err := http.NewCrossOriginProtection().Check(r); err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	points, errs := influx.Parse(http.MaxBytesReader(w, r.Body, maxWriteBody))
+	props, mapErrs := s.systemService.WriteLineProtocol(points)
+	errs = append(errs, mapErrs...)
+
+	resp := protocol.WriteResponse{Status: "ok", Written: len(props), Props: props}
+	for _, err := range errs {
+		le := protocol.LineError{Error: err.Error()}
+		if e, ok := err.(*influx.LineError); ok {
+			le = protocol.LineError{Line: e.Line, Error: e.Err.Error()}
+		}
+		resp.Errors = append(resp.Errors, le)
+	}
+	sort.SliceStable(resp.Errors, func(i, j int) bool { return resp.Errors[i].Line < resp.Errors[j].Line })
+
+	status := http.StatusOK
+	if len(resp.Errors) > 0 {
+		resp.Status, status = "partial", http.StatusBadRequest
+		if resp.Written == 0 {
+			resp.Status = "error"
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode write response", "error", err)
+	}
+}
+
+// handleAction triggers a ConfigButton action, the same as pressing the button
+// in the Settings panel. Requiring application/json forces a CORS preflight,
+// which this server never answers, so web pages cannot trigger actions.
+func (s *APIService) handleAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !requireContentType(w, r, "application/json") {
+		return
+	}
+
+	var req protocol.ActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid JSON", http.StatusBadRequest)
+		return
+	}
+	if req.ModuleID == "" || req.Action == "" {
+		http.Error(w, "module_id and action required", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.systemService.TriggerAction(req.ModuleID, req.Action); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(protocol.SidecarResponse{Status: "ok"}); err != nil {
+		slog.Error("Failed to encode action response", "moduleId", req.ModuleID, "error", err)
+	}
+}
+
+// handleMetrics exposes all widgets in Prometheus text format.
+// See metrics.go for the mapping rules.
+func (s *APIService) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := writeMetrics(w, s.systemService.GetStats("")); err != nil {
+		slog.Error("Failed to write metrics", "error", err)
+	}
+}
+
+// handleAlerts returns the current state of every configured alert rule.
+func (s *APIService) handleAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.systemService.GetAlerts()); err != nil {
+		slog.Error("Failed to encode alerts response", "error", err)
+	}
+}
+
 func (s *APIService) handleStatsPull(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -95,3 +358,156 @@ func (s *APIService) handleStatsPull(w http.ResponseWriter, r *http.Request) {
 		slog.Error("Failed to encode stats response", "error", err)
 	}
 }
+
+// handleStream serves widget updates as Server-Sent Events. It first sends a
+// "snapshot" event (same body as GET /api/stats), then one "update" event per
+// protocol.UpdateEvent. Supports the same ?id= filter as /api/stats.
+func (s *APIService) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	filterID := r.URL.Query().Get("id")
+
+	// Subscribe before taking the snapshot so no update falls in between.
+	events, cancel := s.systemService.subscribeUpdates()
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	if err := writeSSE(w, "snapshot", s.systemService.GetStats(filterID)); err != nil {
+		return
+	}
+	flusher.Flush()
+
+	keepalive := time.NewTicker(StreamKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			if _, err := w.Write([]byte(": keepalive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+			if filterID != "" && ev.ID != filterID {
+				continue
+			}
+			if err := writeSSE(w, "update", ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE writes a single named SSE event with a JSON-encoded data line.
+func writeSSE(w http.ResponseWriter, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("Failed to encode stream event", "event", event, "error", err)
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
+
+// handleHistory returns recorded values for one widget.
+//
+//	GET /api/history?id=glancehud.core.cpu&from=1700000000&to=1700003600&step=30s
+//
+// from/to accept Unix seconds or RFC 3339; step accepts a Go duration ("30s")
+// or plain seconds. All three are optional.
+func (s *APIService) handleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	q := r.URL.Query()
+	id := q.Get("id")
+	if id == "" {
+		http.Error(w, "id required", http.StatusBadRequest)
+		return
+	}
+	from, err := parseTimeParam(q.Get("from"))
+	if err != nil {
+		http.Error(w, "invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTimeParam(q.Get("to"))
+	if err != nil {
+		http.Error(w, "invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	step, err := parseStepParam(q.Get("step"))
+	if err != nil {
+		http.Error(w, "invalid step: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp, err := s.systemService.GetHistory(id, from, to, step.Milliseconds())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		slog.Error("Failed to encode history response", "id", id, "error", err)
+	}
+}
+
+// parseTimeParam parses Unix seconds or RFC 3339 into Unix milliseconds.
+// An empty string yields 0 (unbounded).
+func parseTimeParam(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return sec * 1000, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return 0, err
+	}
+	return t.UnixMilli(), nil
+}
+
+// parseStepParam parses a Go duration or plain seconds. Empty yields 0.
+func parseStepParam(v string) (time.Duration, error) {
+	if v == "" {
+		return 0, nil
+	}
+	if sec, err := strconv.ParseInt(v, 10, 64); err == nil {
+		return time.Duration(sec) * time.Second, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// requireContentType reports whether the request body is of media type want,
+// replying 415 Unsupported Media Type when it is not.
+func requireContentType(w http.ResponseWriter, r *http.Request, want string) bool {
+	mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || mt != want {
+		http.Error(w, fmt.Sprintf("Content-Type must be %s", want), http.StatusUnsupportedMediaType)
+		return false
+	}
+	return true
+}
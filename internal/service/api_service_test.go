@@ -0,0 +1,393 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+)
+
+// readSSE reads the next event from an SSE stream, skipping comment lines.
+func readSSE(t *testing.T, r *bufio.Reader) (event string, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("read stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event != "" {
+				return event, data
+			}
+		case strings.HasPrefix(line, ":"):
+			// comment / keepalive
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openStream(t *testing.T, api *APIService, query string) (*bufio.Reader, func()) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(api.handleStream))
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+query, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET stream: %v", err)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type: want text/event-stream, got %q", ct)
+	}
+	return bufio.NewReader(resp.Body), func() {
+		cancel()
+		_ = resp.Body.Close()
+		srv.Close()
+	}
+}
+
+func TestHandleStream_SnapshotThenUpdates(t *testing.T) {
+	s := newTestService(t)
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+	api := NewAPIService(s)
+
+	r, closeStream := openStream(t, api, "")
+	defer closeStream()
+
+	event, data := readSSE(t, r)
+	if event != "snapshot" {
+		t.Fatalf("first event: want snapshot, got %q", event)
+	}
+	var snap protocol.StatsResponse
+	if err := json.Unmarshal([]byte(data), &snap); err != nil {
+		t.Fatalf("snapshot JSON: %v", err)
+	}
+	if _, ok := snap.Widgets["custom.x"]; !ok {
+		t.Errorf("snapshot missing custom.x: %+v", snap.Widgets)
+	}
+
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 7.0})
+
+	event, data = readSSE(t, r)
+	if event != "update" {
+		t.Fatalf("want update event, got %q", event)
+	}
+	var ev protocol.UpdateEvent
+	if err := json.Unmarshal([]byte(data), &ev); err != nil {
+		t.Fatalf("update JSON: %v", err)
+	}
+	if ev.ID != "custom.x" || ev.Data.Value != 7.0 {
+		t.Errorf("unexpected update: %+v", ev)
+	}
+}
+
+func TestHandleStream_FilterByID(t *testing.T) {
+	s := newTestService(t)
+	s.sources["custom.a"] = newTestSidecar("custom.a")
+	s.sources["custom.b"] = newTestSidecar("custom.b")
+	api := NewAPIService(s)
+
+	r, closeStream := openStream(t, api, "?id=custom.b")
+	defer closeStream()
+	readSSE(t, r) // snapshot
+
+	s.UpdateSidecarData("custom.a", &protocol.DataPayload{Value: 1.0})
+	s.UpdateSidecarData("custom.b", &protocol.DataPayload{Value: 2.0})
+
+	done := make(chan protocol.UpdateEvent, 1)
+	go func() {
+		_, data := readSSE(t, r)
+		var ev protocol.UpdateEvent
+		_ = json.Unmarshal([]byte(data), &ev)
+		done <- ev
+	}()
+
+	select {
+	case ev := <-done:
+		if ev.ID != "custom.b" {
+			t.Errorf("filter leaked event for %q", ev.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for filtered update")
+	}
+}
+
+func TestHandleStream_MethodNotAllowed(t *testing.T) {
+	api := NewAPIService(newTestService(t))
+	rec := httptest.NewRecorder()
+	api.handleStream(rec, httptest.NewRequest(http.MethodPost, "/api/stream", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("want 405, got %d", rec.Code)
+	}
+}
+
+// --- WebSocket sidecar transport ---
+
+func dialWidgetSocket(t *testing.T, api *APIService) (*websocket.Conn, func()) {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(api.handleWidgetSocket))
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	c, _, err := websocket.Dial(context.Background(), url, nil)
+	if err != nil {
+		srv.Close()
+		t.Fatalf("dial: %v", err)
+	}
+	return c, func() {
+		c.CloseNow()
+		srv.Close()
+	}
+}
+
+func writeFrame(t *testing.T, c *websocket.Conn, req protocol.SidecarRequest) {
+	t.Helper()
+	data, _ := json.Marshal(req)
+	if err := c.Write(context.Background(), websocket.MessageText, data); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func readFrame(t *testing.T, c *websocket.Conn) protocol.SidecarResponse {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, data, err := c.Read(ctx)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var resp protocol.SidecarResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("response JSON: %v", err)
+	}
+	return resp
+}
+
+func TestHandleWidgetSocket_PushesSettingsOnApplyConfig(t *testing.T) {
+	s := newTestService(t)
+	sc := newTestSidecar("custom.ws")
+	sc.currentProps = map[string]interface{}{"gpu_index": 0.0}
+	s.sources["custom.ws"] = sc
+	api := NewAPIService(s)
+
+	c, done := dialWidgetSocket(t, api)
+	defer done()
+
+	writeFrame(t, c, protocol.SidecarRequest{ModuleID: "custom.ws", Data: &protocol.DataPayload{Value: 1.0}})
+	first := readFrame(t, c)
+	if first.Status != "ok" || first.ModuleID != "custom.ws" || first.Props["gpu_index"] != 0.0 {
+		t.Fatalf("unexpected initial frame: %+v", first)
+	}
+
+	// Simulate the user saving new settings
+	s.mu.Lock()
+	sc.ApplyConfig(map[string]interface{}{"gpu_index": 1.0})
+	s.mu.Unlock()
+
+	pushed := readFrame(t, c)
+	if pushed.ModuleID != "custom.ws" || pushed.Props["gpu_index"] != 1.0 {
+		t.Errorf("unexpected pushed frame: %+v", pushed)
+	}
+}
+
+func TestHandleWidgetSocket_InvalidFrame(t *testing.T) {
+	api := NewAPIService(newTestService(t))
+	c, done := dialWidgetSocket(t, api)
+	defer done()
+
+	writeFrame(t, c, protocol.SidecarRequest{})
+	resp := readFrame(t, c)
+	if resp.Status != "error" || resp.Error == "" {
+		t.Errorf("expected error frame, got %+v", resp)
+	}
+}
+
+func TestHandleWidgetSocket_DisconnectMarksOffline(t *testing.T) {
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+	s.sources["custom.ws"] = newTestSidecar("custom.ws")
+	api := NewAPIService(s)
+
+	c, done := dialWidgetSocket(t, api)
+	defer done()
+
+	writeFrame(t, c, protocol.SidecarRequest{ModuleID: "custom.ws", Data: &protocol.DataPayload{Value: 1.0}})
+	readFrame(t, c)
+
+	_ = c.Close(websocket.StatusNormalClosure, "")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if s.GetStats("custom.ws").Widgets["custom.ws"].IsOffline {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("sidecar not marked offline after socket close")
+}
+
+// --- History ---
+
+func TestHandleHistory_RecordsEmittedValues(t *testing.T) {
+	s := newTestService(t)
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+	api := NewAPIService(s)
+
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 10.0})
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: "n/a"}) // non-numeric: skipped
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 20.0})
+
+	rec := httptest.NewRecorder()
+	api.handleHistory(rec, httptest.NewRequest(http.MethodGet, "/api/history?id=custom.x", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp protocol.HistoryResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if len(resp.Points) != 2 || resp.Points[0].V != 10 || resp.Points[1].V != 20 {
+		t.Errorf("unexpected points: %+v", resp.Points)
+	}
+}
+
+func TestHandleHistory_BadParams(t *testing.T) {
+	s := newTestService(t)
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+	api := NewAPIService(s)
+
+	cases := map[string]int{
+		"/api/history":                     http.StatusBadRequest,
+		"/api/history?id=custom.x&from=xx": http.StatusBadRequest,
+		"/api/history?id=custom.x&step=1q": http.StatusBadRequest,
+		"/api/history?id=missing":          http.StatusNotFound,
+		"/api/history?id=custom.x&step=30": http.StatusOK,
+	}
+	for url, want := range cases {
+		rec := httptest.NewRecorder()
+		api.handleHistory(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		if rec.Code != want {
+			t.Errorf("%s: want %d, got %d", url, want, rec.Code)
+		}
+	}
+}
+
+// --- Request guards ---
+
+func TestAPI_RejectsWrongContentType(t *testing.T) {
+	s := newTestService(t)
+	sc := newTestSidecar("custom.train")
+	sc.schema = []protocol.ConfigSchema{
+		{Label: "Restart", Type: protocol.ConfigButton, Action: "restart"},
+	}
+	s.sources["custom.train"] = sc
+	api := NewAPIService(s)
+
+	action := `{"module_id":"custom.train","action":"restart"}`
+	for _, tc := range []struct {
+		name    string
+		handler http.HandlerFunc
+		url     string
+		body    string
+		ctype   string
+		want    int
+	}{
+		{"action text/plain", api.handleAction, "/api/action", action, "text/plain", http.StatusUnsupportedMediaType},
+		{"action missing", api.handleAction, "/api/action", action, "", http.StatusUnsupportedMediaType},
+		{"action json", api.handleAction, "/api/action", action, "application/json; charset=utf-8", http.StatusOK},
+		{"write form", api.handleWrite, "/api/write", "x value=1", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"write json", api.handleWrite, "/api/write", "x value=1", "application/json", http.StatusUnsupportedMediaType},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.url, strings.NewReader(tc.body))
+		if tc.ctype != "" {
+			req.Header.Set("Content-Type", tc.ctype)
+		}
+		rec := httptest.NewRecorder()
+		tc.handler(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: want %d, got %d", tc.name, tc.want, rec.Code)
+		}
+	}
+	if acts := s.takeSidecarActions("custom.train"); len(acts) != 1 {
+		t.Errorf("only the JSON request should queue an action, got %v", acts)
+	}
+}
+
+func TestHandleWrite_RejectsCrossSite(t *testing.T) {
+	api := NewAPIService(newTestService(t))
+	req := httptest.NewRequest(http.MethodPost, "/api/write", strings.NewReader("x value=1"))
+	req.Header.Set("Content-Type", "text/plain")
+	req.Header.Set("Sec-Fetch-Site", "cross-site")
+	rec := httptest.NewRecorder()
+	api.handleWrite(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("want 403, got %d", rec.Code)
+	}
+}
+
+// --- Line protocol ---
+
+func TestHandleWrite_LineProtocol(t *testing.T) {
+	s := newTestService(t)
+	cfg := s.GetConfig()
+	cfg.Influx = []modules.InfluxRule{{Measurement: "queue", ID: "influx.queue.{name}", Value: "depth"}}
+	if err := s.configService.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	api := NewAPIService(s)
+
+	body := "queue,name=mail depth=12i\nqueue,name=mail depth=\nmem used=1\n"
+	req := httptest.NewRequest(http.MethodPost, "/api/write", strings.NewReader(body))
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	rec := httptest.NewRecorder()
+	api.handleWrite(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("partial write: want 400, got %d", rec.Code)
+	}
+	var resp protocol.WriteResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("JSON: %v", err)
+	}
+	if resp.Status != "partial" || resp.Written != 1 || len(resp.Errors) != 2 ||
+		resp.Errors[0].Line != 2 || resp.Errors[1].Line != 3 {
+		t.Errorf("response: %+v", resp)
+	}
+	if _, ok := resp.Props["influx.queue.mail"]; !ok {
+		t.Errorf("want props for the written widget, got %+v", resp.Props)
+	}
+
+	sc, ok := s.sources["influx.queue.mail"].(*SidecarSource)
+	if !ok || sc.config.Type != protocol.TypeSpark {
+		t.Fatalf("want registered sparkline sidecar, got %+v", s.sources["influx.queue.mail"])
+	}
+	if got := s.cache["influx.queue.mail"]; got == nil || got.Value != 12.0 {
+		t.Errorf("cached payload: %+v", got)
+	}
+
+	// Wait for lazy registration to persist the widget
+	for i := 0; i < 100; i++ {
+		if slices.ContainsFunc(s.GetConfig().Widgets, func(w modules.WidgetConfig) bool { return w.ID == "influx.queue.mail" }) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec = httptest.NewRecorder()
+	api.handleWrite(rec, httptest.NewRequest(http.MethodGet, "/api/write", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("want 405, got %d", rec.Code)
+	}
+}
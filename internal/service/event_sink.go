@@ -0,0 +1,24 @@
+package service
+
+// EventSink receives backend events destined for the UI ("stats:update",
+// "config:reload", "mode:change", ...). In desktop mode it forwards to the
+// Wails event bus; in headless mode there is no window to notify, so the sink
+// may log, fan out to HTTP subscribers, or drop events entirely.
+type EventSink interface {
+	Emit(name string, data any)
+}
+
+// EventSinkFunc adapts an ordinary function to the EventSink interface.
+type EventSinkFunc func(name string, data any)
+
+// Emit calls f(name, data).
+func (f EventSinkFunc) Emit(name string, data any) {
+	f(name, data)
+}
+
+// DesktopNotifier is optionally implemented by an EventSink that can raise OS
+// notifications. Alert channels of type "desktop" fail with
+// notify.ErrDesktopUnavailable when the attached sink does not implement it.
+type DesktopNotifier interface {
+	Notify(title, body string) error
+}
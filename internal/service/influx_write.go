@@ -0,0 +1,368 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"glancehud/internal/influx"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"glancehud/internal/statsd"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultInfluxID = "influx.{measurement}"
+
+// maxInfluxWidgets caps how many widgets line-protocol writes may register,
+// so a high-cardinality tag in the widget ID cannot create them unbounded.
+const maxInfluxWidgets = 500
+
+// influxWidget collects the points of one write that map to the same widget.
+type influxWidget struct {
+	id     string
+	rule   *modules.InfluxRule
+	points []influx.Point
+}
+
+// WriteLineProtocol feeds parsed line-protocol points into sidecar widgets.
+// Each point goes to the first AppConfig.Influx rule it matches; every
+// resulting widget is registered like a pushed sidecar and updated through
+// UpdateSidecarData. It returns the current props of each updated widget,
+// and a *influx.LineError for every point that could not be mapped.
+func (s *SystemService) WriteLineProtocol(points []influx.Point) (map[string]map[string]any, []error) {
+	widgets, errs := mapInfluxPoints(s.GetConfig().Influx, points)
+	props := make(map[string]map[string]any, len(widgets))
+	for _, w := range widgets {
+		data, perr := w.payload()
+		errs = append(errs, perr...)
+		if data == nil {
+			continue
+		}
+		if err := s.ensureInfluxWidget(w); err != nil {
+			errs = append(errs, &influx.LineError{Line: w.points[0].Line, Err: err})
+			continue
+		}
+		props[w.id] = s.updateIngestedData(w.id, data)
+	}
+	return props, errs
+}
+
+// ensureInfluxWidget registers w's sidecar unless a source with its template
+// exists already. Writes are serialised here so concurrent first writes to a
+// new ID register, and persist, it once.
+func (s *SystemService) ensureInfluxWidget(w *influxWidget) error {
+	tmpl := w.template()
+	s.influxMu.Lock()
+	defer s.influxMu.Unlock()
+
+	s.mu.RLock()
+	src, exists := s.sources[w.id]
+	current := exists
+	if sc, ok := src.(*SidecarSource); ok {
+		// Sources restored from config lack the rule's props and schema
+		want := tmpl
+		want.ID = w.id
+		current = reflect.DeepEqual(sc.config, want) && reflect.DeepEqual(sc.schema, w.rule.Schema)
+	}
+	live := 0
+	for id := range s.influxIDs {
+		if _, ok := s.sources[id]; ok {
+			live++
+		} else {
+			delete(s.influxIDs, id) // removed by the user
+		}
+	}
+	s.mu.RUnlock()
+
+	if current {
+		return nil
+	}
+	if !exists && live >= maxInfluxWidgets {
+		return fmt.Errorf("widget %q not created: at most %d influx widgets", w.id, maxInfluxWidgets)
+	}
+	s.RegisterSidecar(w.id, &tmpl, w.rule.Schema)
+	s.influxIDs[w.id] = struct{}{}
+	return nil
+}
+
+// mapInfluxPoints groups points by widget, in order of first appearance.
+func mapInfluxPoints(rules []modules.InfluxRule, points []influx.Point) ([]*influxWidget, []error) {
+	var widgets []*influxWidget
+	byID := make(map[string]*influxWidget)
+	var errs []error
+	for _, p := range points {
+		rule := matchInfluxRule(rules, p)
+		if rule == nil {
+			errs = append(errs, &influx.LineError{Line: p.Line, Err: fmt.Errorf("no influx rule matches measurement %q", p.Measurement)})
+			continue
+		}
+		id, err := influxWidgetID(rule.ID, p)
+		if err != nil {
+			errs = append(errs, &influx.LineError{Line: p.Line, Err: err})
+			continue
+		}
+		w, ok := byID[id]
+		if !ok {
+			w = &influxWidget{id: id, rule: rule}
+			byID[id] = w
+			widgets = append(widgets, w)
+		}
+		w.points = append(w.points, p)
+	}
+	return widgets, errs
+}
+
+func matchInfluxRule(rules []modules.InfluxRule, p influx.Point) *modules.InfluxRule {
+	for i := range rules {
+		r := &rules[i]
+		if r.Measurement != "*" && r.Measurement != p.Measurement {
+			continue
+		}
+		matched := true
+		for k, v := range r.Tags {
+			if p.Tags[k] != v {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return r
+		}
+	}
+	return nil
+}
+
+// influxWidgetID expands "{measurement}" and "{<tag>}" in tmpl. Substituted
+// values are sanitised like StatsD names so clients cannot inject arbitrary
+// characters into widget IDs.
+func influxWidgetID(tmpl string, p influx.Point) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultInfluxID
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(tmpl, '{')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(tmpl[open:], '}')
+		if end < 0 {
+			break
+		}
+		name := tmpl[open+1 : open+end]
+		val, ok := p.Tags[name]
+		if name == "measurement" {
+			val, ok = p.Measurement, true
+		}
+		if !ok {
+			return "", fmt.Errorf("widget id %q needs tag %q", tmpl, name)
+		}
+		b.WriteString(tmpl[:open])
+		b.WriteString(statsd.Sanitize(val))
+		tmpl = tmpl[open+end+1:]
+	}
+	b.WriteString(tmpl)
+	return b.String(), nil
+}
+
+// template is the rule's render config, defaulting to a sparkline titled
+// with the widget ID.
+func (w *influxWidget) template() protocol.RenderConfig {
+	tmpl := w.rule.Template
+	if tmpl.Type == "" {
+		tmpl.Type = protocol.TypeSpark
+	}
+	if tmpl.Title == "" {
+		tmpl.Title = w.id
+	}
+	return tmpl
+}
+
+// payload builds the widget's data. Without RowTag the points' fields are
+// merged and each field is a row and a series; with RowTag each point is one
+// row, keyed by its tag value, and Value is their sum. Points that lack the
+// value field are reported as line errors.
+func (w *influxWidget) payload() (*protocol.DataPayload, []error) {
+	if w.rule.RowTag != "" {
+		return w.rowPayload()
+	}
+
+	fields := make(map[string]any)
+	for _, p := range w.points {
+		for k, v := range p.Fields {
+			fields[k] = v
+		}
+	}
+	last := w.points[len(w.points)-1].Line
+	data := &protocol.DataPayload{Series: make(map[string]float64)}
+	if v, ok, err := w.value(fields); err != nil {
+		return nil, []error{&influx.LineError{Line: last, Err: err}}
+	} else if ok {
+		data.Value = v
+	}
+	for k, v := range fields {
+		if f, ok := influxNumber(v); ok {
+			data.Series[k] = f
+		}
+	}
+
+	names := w.rule.Fields
+	if len(names) == 0 {
+		for k := range fields {
+			names = append(names, k)
+		}
+		sort.Strings(names)
+	}
+	tmpl := w.template()
+	switch tmpl.Type {
+	case protocol.TypeBarList:
+		items := make([]protocol.BarListItem, 0, len(names))
+		for _, k := range names {
+			if f, ok := influxNumber(fields[k]); ok {
+				items = append(items, protocol.BarListItem{Label: k, Percent: w.percent(f), Value: formatInflux(fields[k])})
+			}
+		}
+		data.Items = items
+	case protocol.TypeKeyValue:
+		items := make([]protocol.KeyValueItem, 0, len(names))
+		for _, k := range names {
+			if v, ok := fields[k]; ok {
+				items = append(items, protocol.KeyValueItem{Key: k, Value: formatInflux(v)})
+			}
+		}
+		data.Items = items
+	case protocol.TypeSpark:
+		// Several fields and no single value: draw each numeric field
+		if _, ok := tmpl.Props["series"]; !ok && data.Value == nil {
+			var lines []string
+			for _, k := range names {
+				if _, ok := data.Series[k]; ok {
+					lines = append(lines, k)
+				}
+			}
+			data.Props = map[string]any{"series": seriesLines(lines)}
+		}
+	}
+	return data, nil
+}
+
+func (w *influxWidget) rowPayload() (*protocol.DataPayload, []error) {
+	var errs []error
+	rows := make(map[string]float64)
+	for _, p := range w.points {
+		key, ok := p.Tags[w.rule.RowTag]
+		if !ok {
+			errs = append(errs, &influx.LineError{Line: p.Line, Err: fmt.Errorf("missing row tag %q", w.rule.RowTag)})
+			continue
+		}
+		v, ok, err := w.value(p.Fields)
+		if err == nil && !ok {
+			err = errors.New("no value field: set the rule's value")
+		}
+		if err != nil {
+			errs = append(errs, &influx.LineError{Line: p.Line, Err: err})
+			continue
+		}
+		rows[key] = v
+	}
+	if len(rows) == 0 {
+		return nil, errs
+	}
+
+	keys := make([]string, 0, len(rows))
+	total := 0.0
+	for k, v := range rows {
+		keys = append(keys, k)
+		total += v
+	}
+	sort.Strings(keys)
+	data := &protocol.DataPayload{Value: total, Series: rows}
+	tmpl := w.template()
+	switch tmpl.Type {
+	case protocol.TypeBarList:
+		items := make([]protocol.BarListItem, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, protocol.BarListItem{Label: k, Percent: w.percent(rows[k]), Value: formatInflux(rows[k])})
+		}
+		data.Items = items
+	case protocol.TypeKeyValue:
+		items := make([]protocol.KeyValueItem, 0, len(keys))
+		for _, k := range keys {
+			items = append(items, protocol.KeyValueItem{Key: k, Value: formatInflux(rows[k])})
+		}
+		data.Items = items
+	case protocol.TypeSpark:
+		if _, ok := tmpl.Props["series"]; !ok {
+			data.Props = map[string]any{"series": seriesLines(keys)}
+		}
+	}
+	return data, errs
+}
+
+// value picks the number for DataPayload.Value: the rule's field, "value",
+// or the only numeric field. ok is false when none applies.
+func (w *influxWidget) value(fields map[string]any) (v float64, ok bool, err error) {
+	name := w.rule.Value
+	if name == "" {
+		if _, has := fields["value"]; has {
+			name = "value"
+		} else {
+			for k, fv := range fields {
+				if _, num := influxNumber(fv); num {
+					if name != "" {
+						return 0, false, nil // ambiguous
+					}
+					name = k
+				}
+			}
+			if name == "" {
+				return 0, false, nil
+			}
+		}
+	}
+	raw, has := fields[name]
+	if !has {
+		return 0, false, fmt.Errorf("missing field %q", name)
+	}
+	if v, ok = influxNumber(raw); !ok {
+		return 0, false, fmt.Errorf("field %q is not numeric", name)
+	}
+	return v, true, nil
+}
+
+// percent scales v by template props.max, or treats it as a percentage.
+func (w *influxWidget) percent(v float64) float64 {
+	if scale, ok := w.rule.Template.Props["max"].(float64); ok && scale > 0 {
+		v = v / scale * 100
+	}
+	return math.Round(math.Max(0, math.Min(100, v)))
+}
+
+// influxNumber converts numeric and boolean field values.
+func influxNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+func formatInflux(v any) string {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"glancehud/internal/influx"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func parsePoints(t *testing.T, body string) []influx.Point {
+	t.Helper()
+	points, errs := influx.Parse(strings.NewReader(body))
+	if len(errs) > 0 {
+		t.Fatalf("parse: %v", errs)
+	}
+	return points
+}
+
+func TestMapInfluxPoints(t *testing.T) {
+	rules := []modules.InfluxRule{
+		{Measurement: "cpu", Tags: map[string]string{"region": "eu"}, ID: "eu.cpu"},
+		{Measurement: "cpu", ID: "influx.{measurement}.{host}"},
+		{Measurement: "*"},
+	}
+	points := parsePoints(t, `cpu,host=web1,region=eu usage=1
+cpu,host=web2 usage=2
+cpu,host=web2 idle=98
+cpu usage=3
+mem used=4`)
+
+	widgets, errs := mapInfluxPoints(rules, points)
+	var ids []string
+	for _, w := range widgets {
+		ids = append(ids, w.id)
+	}
+	if strings.Join(ids, " ") != "eu.cpu influx.cpu.web2 influx.mem" {
+		t.Errorf("ids: %v", ids)
+	}
+	if len(widgets) == 3 && len(widgets[1].points) != 2 {
+		t.Errorf("points for the same widget should be grouped: %+v", widgets[1])
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `line 4`) || !strings.Contains(errs[0].Error(), `"host"`) {
+		t.Errorf("errs: %v", errs)
+	}
+
+	if _, errs := mapInfluxPoints(nil, points[:1]); len(errs) != 1 {
+		t.Errorf("unmatched points should be errors, got %v", errs)
+	}
+}
+
+func TestInfluxWidget_Payload(t *testing.T) {
+	// Fields of one point (or several merged) become rows and series
+	w := &influxWidget{
+		id:     "influx.disk",
+		rule:   &modules.InfluxRule{Value: "used_percent", Template: protocol.RenderConfig{Type: protocol.TypeBarList}},
+		points: parsePoints(t, "disk used_percent=71.5,inodes=12\ndisk free=40i,mount=\"/\""),
+	}
+	data, errs := w.payload()
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if data.Value != 71.5 || data.Series["free"] != 40 || data.Series["inodes"] != 12 {
+		t.Errorf("got %+v", data)
+	}
+	items := data.Items.([]protocol.BarListItem)
+	if len(items) != 3 || items[2] != (protocol.BarListItem{Label: "used_percent", Percent: 72, Value: "71.5"}) {
+		t.Errorf("items: %+v", items)
+	}
+
+	w.rule.Value = "missing"
+	if data, errs := w.payload(); data != nil || len(errs) != 1 {
+		t.Errorf("missing value field: %+v, %v", data, errs)
+	}
+
+	// RowTag: one row per tag value
+	w = &influxWidget{
+		id: "influx.queues",
+		rule: &modules.InfluxRule{RowTag: "queue", Template: protocol.RenderConfig{
+			Type: protocol.TypeBarList, Props: map[string]any{"max": 200.0},
+		}},
+		points: parsePoints(t, "q,queue=mail depth=100\nq,queue=sms depth=50\nq depth=1\nq,queue=push a=1,b=2"),
+	}
+	data, errs = w.payload()
+	if len(errs) != 2 {
+		t.Errorf("want errors for the untagged and ambiguous points, got %v", errs)
+	}
+	items = data.Items.([]protocol.BarListItem)
+	if data.Value != 150.0 || len(items) != 2 || items[0] != (protocol.BarListItem{Label: "mail", Percent: 50, Value: "100"}) {
+		t.Errorf("rows: %+v %+v", data, items)
+	}
+
+	// Sparkline rows get their lines generated
+	w.rule.Template = protocol.RenderConfig{}
+	data, _ = w.payload()
+	if series, _ := data.Props["series"].([]map[string]any); len(series) != 2 || series[1]["key"] != "sms" {
+		t.Errorf("series props: %+v", data.Props)
+	}
+}
+
+func TestWriteLineProtocol_RegistersOnce(t *testing.T) {
+	s := newTestService(t)
+	cfg := s.GetConfig()
+	cfg.Influx = []modules.InfluxRule{{Measurement: "jobs", ID: "influx.jobs.{host}"}}
+	if err := s.configService.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent first writes must persist the new widget once
+	points := parsePoints(t, "jobs,host=web/1 value=3")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.WriteLineProtocol(points)
+		}()
+	}
+	wg.Wait()
+
+	const id = "influx.jobs.web_1" // tag values are sanitised
+	if _, ok := s.sources[id]; !ok {
+		t.Fatalf("want %s registered, got %v", id, s.sources)
+	}
+	count := func() int {
+		n := 0
+		for _, w := range s.GetConfig().Widgets {
+			if w.ID == id {
+				n++
+			}
+		}
+		return n
+	}
+	for i := 0; i < 100 && count() == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond) // let any duplicate writer land
+	if n := count(); n != 1 {
+		t.Errorf("want 1 persisted widget, got %d", n)
+	}
+}
+
+func TestWriteLineProtocol_CapsWidgets(t *testing.T) {
+	s := newTestService(t)
+	cfg := s.GetConfig()
+	cfg.Influx = []modules.InfluxRule{{Measurement: "*", ID: "influx.{measurement}.{host}"}}
+	if err := s.configService.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < maxInfluxWidgets; i++ {
+		id := fmt.Sprintf("influx.fill.%d", i)
+		s.sources[id] = newTestSidecar(id)
+		s.influxIDs[id] = struct{}{}
+	}
+
+	props, errs := s.WriteLineProtocol(parsePoints(t, "cpu,host=new value=1"))
+	if len(props) != 0 || len(errs) != 1 || !strings.Contains(errs[0].Error(), "at most") {
+		t.Fatalf("want cap error, got %v %v", props, errs)
+	}
+
+	// Removing a widget frees its slot
+	delete(s.sources, "influx.fill.0")
+	if _, errs := s.WriteLineProtocol(parsePoints(t, "cpu,host=new value=1")); len(errs) != 0 {
+		t.Errorf("want write accepted after removal, got %v", errs)
+	}
+	for i := 0; i < 100 && len(s.GetConfig().Widgets) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"glancehud/internal/protocol"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Prometheus exposition for GET /metrics.
+//
+// Mapping rules (all series are labelled with the widget render ID):
+//
+//	glancehud_widget_up{id}                  1 if the widget is live, 0 if a sidecar is offline
+//	glancehud_widget_value{id}               DataPayload.Value, when numeric
+//	glancehud_widget_item_percent{id,label}  BarListItem.Percent, one series per Label
+//
+// Widgets without cached data still report glancehud_widget_up.
+
+type metricFamily struct {
+	name    string
+	help    string
+	samples []string
+}
+
+func (f *metricFamily) add(labels string, v float64) {
+	f.samples = append(f.samples, fmt.Sprintf("%s{%s} %g", f.name, labels, v))
+}
+
+func (f *metricFamily) writeTo(w io.Writer) error {
+	if len(f.samples) == 0 {
+		return nil
+	}
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", f.name, f.help, f.name); err != nil {
+		return err
+	}
+	for _, line := range f.samples {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMetrics renders a stats snapshot in Prometheus text format 0.0.4.
+func writeMetrics(w io.Writer, stats protocol.StatsResponse) error {
+	up := &metricFamily{name: "glancehud_widget_up", help: "Whether the widget is receiving data (0 = sidecar offline)."}
+	value := &metricFamily{name: "glancehud_widget_value", help: "Numeric widget value (DataPayload.value)."}
+	percent := &metricFamily{name: "glancehud_widget_item_percent", help: "Bar-list item percentage (BarListItem.percent)."}
+
+	ids := make([]string, 0, len(stats.Widgets))
+	for id := range stats.Widgets {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		entry := stats.Widgets[id]
+		idLabel := fmt.Sprintf(`id="%s"`, escapeLabel(id))
+
+		upValue := 1.0
+		if entry.IsOffline {
+			upValue = 0
+		}
+		up.add(idLabel, upValue)
+
+		if entry.Data == nil {
+			continue
+		}
+		if v, ok := numericValue(entry.Data.Value); ok {
+			value.add(idLabel, v)
+		}
+		if entry.Type == protocol.TypeBarList {
+			for _, item := range barListItems(entry.Data.Items) {
+				percent.add(fmt.Sprintf(`%s,label="%s"`, idLabel, escapeLabel(item.Label)), item.Percent)
+			}
+		}
+	}
+
+	for _, f := range []*metricFamily{up, value, percent} {
+		if err := f.writeTo(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// barListItems normalises DataPayload.Items into BarListItems. Native modules
+// store typed slices; sidecar items arrive as decoded JSON ([]any of maps).
+func barListItems(items any) []protocol.BarListItem {
+	switch v := items.(type) {
+	case nil:
+		return nil
+	case []protocol.BarListItem:
+		return v
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil
+	}
+	var out []protocol.BarListItem
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
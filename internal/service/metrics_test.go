@@ -0,0 +1,67 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"glancehud/internal/protocol"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics_MappingRules(t *testing.T) {
+	// Sidecar items arrive as decoded JSON, not typed structs
+	var sidecarItems any
+	_ = json.Unmarshal([]byte(`[{"label":"python \"train\"","percent":42.5,"value":"x"}]`), &sidecarItems)
+
+	stats := protocol.StatsResponse{Widgets: map[string]protocol.StatEntry{
+		"glancehud.core.cpu": {
+			ID: "glancehud.core.cpu", Type: protocol.TypeSpark,
+			Data: &protocol.DataPayload{Value: 12.5},
+		},
+		"glancehud.core.disk": {
+			ID: "glancehud.core.disk", Type: protocol.TypeBarList,
+			Data: &protocol.DataPayload{Items: []protocol.BarListItem{{Label: "/", Percent: 80}}},
+		},
+		"gpu.procs": {
+			ID: "gpu.procs", Type: protocol.TypeBarList, IsOffline: true,
+			Data: &protocol.DataPayload{Items: sidecarItems},
+		},
+		"custom.text": {
+			ID: "custom.text", Type: protocol.TypeKeyValue,
+			Data: &protocol.DataPayload{Value: "not a number"},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, stats); err != nil {
+		t.Fatalf("writeMetrics: %v", err)
+	}
+	out := buf.String()
+
+	want := []string{
+		"# TYPE glancehud_widget_up gauge",
+		`glancehud_widget_up{id="glancehud.core.cpu"} 1`,
+		`glancehud_widget_up{id="gpu.procs"} 0`,
+		`glancehud_widget_value{id="glancehud.core.cpu"} 12.5`,
+		`glancehud_widget_item_percent{id="glancehud.core.disk",label="/"} 80`,
+		`glancehud_widget_item_percent{id="gpu.procs",label="python \"train\""} 42.5`,
+	}
+	for _, line := range want {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing line %q in:\n%s", line, out)
+		}
+	}
+	if strings.Contains(out, `glancehud_widget_value{id="custom.text"}`) {
+		t.Error("non-numeric value must not be exported")
+	}
+}
+
+func TestWriteMetrics_EmptyFamiliesOmitted(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeMetrics(&buf, protocol.StatsResponse{}); err != nil {
+		t.Fatalf("writeMetrics: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected empty output, got %q", buf.String())
+	}
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"glancehud/internal/modules"
+	"glancehud/internal/prom"
+	"glancehud/internal/protocol"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// promDecoder selects series from a Prometheus text exposition and folds them
+// into one payload: Value is the sum of every match, and with groupBy each
+// label value becomes a row (bar-list, key-value) or a line (sparkline).
+type promDecoder struct {
+	sel        *prom.Selector
+	rate       bool
+	groupBy    string
+	typ        protocol.ComponentType
+	max        float64 // template props.max; bar percent scale when set
+	seriesProp bool    // template defines its own sparkline series
+
+	mu   sync.Mutex
+	prev map[string]promPoint // last scrape, by series key; rate only
+}
+
+type promPoint struct {
+	value float64
+	at    time.Time
+}
+
+func newPromDecoder(cfg modules.PromScrape, tmpl protocol.RenderConfig) (*promDecoder, error) {
+	if cfg.Series == "" {
+		return nil, errors.New("prometheus.series required")
+	}
+	sel, err := prom.ParseSelector(cfg.Series)
+	if err != nil {
+		return nil, err
+	}
+	d := &promDecoder{sel: sel, rate: cfg.Rate, groupBy: cfg.GroupBy, typ: tmpl.Type}
+	if f, ok := tmpl.Props["max"].(float64); ok && f > 0 {
+		d.max = f
+	}
+	_, d.seriesProp = tmpl.Props["series"]
+	return d, nil
+}
+
+func (d *promDecoder) decode(body []byte, now time.Time) (*protocol.DataPayload, error) {
+	samples, err := prom.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	groups := make(map[string]float64)
+	next := make(map[string]promPoint)
+	primed := false
+	for _, s := range samples {
+		if !d.sel.Match(s) || math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+			continue
+		}
+		group := s.Labels[d.groupBy]
+		if !d.rate {
+			groups[group] += s.Value
+			continue
+		}
+		key := s.Key()
+		next[key] = promPoint{s.Value, now}
+		groups[group] += 0
+		prev, ok := d.prev[key]
+		dt := now.Sub(prev.at).Seconds()
+		if !ok || dt <= 0 {
+			continue
+		}
+		primed = true
+		delta := s.Value - prev.value
+		if delta < 0 {
+			delta = s.Value // counter reset: it restarted from zero
+		}
+		groups[group] += delta / dt
+	}
+	if d.rate {
+		d.prev = next
+		if len(next) > 0 && !primed {
+			return nil, nil
+		}
+	}
+	return d.payload(groups), nil
+}
+
+// payload builds the widget data from per-group sums. Without groupBy there
+// is a single group, labelled with the metric name.
+func (d *promDecoder) payload(groups map[string]float64) *protocol.DataPayload {
+	names := make([]string, 0, len(groups))
+	total := 0.0
+	for name, v := range groups {
+		names = append(names, name)
+		total += v
+	}
+	sort.Strings(names)
+
+	p := &protocol.DataPayload{Value: round3(total), DisplayValue: d.format(total)}
+	if d.groupBy != "" {
+		p.Series = make(map[string]float64, len(groups))
+		for name, v := range groups {
+			p.Series[d.rowLabel(name)] = round3(v)
+		}
+	}
+
+	switch d.typ {
+	case protocol.TypeBarList:
+		items := make([]protocol.BarListItem, 0, len(names))
+		for _, name := range names {
+			v := groups[name]
+			pct := 0.0
+			if d.max > 0 {
+				pct = v / d.max * 100
+			} else if total > 0 {
+				pct = v / total * 100
+			}
+			items = append(items, protocol.BarListItem{
+				Label:   d.rowLabel(name),
+				Percent: math.Round(math.Max(0, math.Min(100, pct))),
+				Value:   d.format(v),
+			})
+		}
+		p.Items = items
+	case protocol.TypeKeyValue:
+		items := make([]protocol.KeyValueItem, 0, len(names))
+		for _, name := range names {
+			items = append(items, protocol.KeyValueItem{Key: d.rowLabel(name), Value: d.format(groups[name])})
+		}
+		p.Items = items
+	case protocol.TypeSpark:
+		if d.groupBy != "" && !d.seriesProp {
+			keys := make([]string, 0, len(names))
+			for _, name := range names {
+				keys = append(keys, d.rowLabel(name))
+			}
+			p.Props = map[string]any{"series": seriesLines(keys)}
+		}
+	}
+	return p
+}
+
+// rowLabel names a group: the label value, or the metric name when not
+// grouping or when a series lacks the label.
+func (d *promDecoder) rowLabel(name string) string {
+	if name == "" {
+		return d.sel.Name
+	}
+	return name
+}
+
+func (d *promDecoder) format(v float64) string {
+	s := strconv.FormatFloat(round3(v), 'f', -1, 64)
+	if d.rate {
+		s += "/s"
+	}
+	return s
+}
+
+func round3(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
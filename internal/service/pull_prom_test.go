@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const promBody = `# TYPE queue_depth gauge
+queue_depth{queue="mail"} 30
+queue_depth{queue="sms"} 10
+queue_depth{queue="push"} NaN
+other_metric 5
+`
+
+func TestPromDecoder_Gauge(t *testing.T) {
+	d, err := newPromDecoder(modules.PromScrape{Series: "queue_depth", GroupBy: "queue"},
+		protocol.RenderConfig{Type: protocol.TypeBarList})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := d.decode([]byte(promBody), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Value != 40.0 || data.Series["mail"] != 30 || data.Series["sms"] != 10 {
+		t.Errorf("got %+v", data)
+	}
+	items := data.Items.([]protocol.BarListItem)
+	want := []protocol.BarListItem{{Label: "mail", Percent: 75, Value: "30"}, {Label: "sms", Percent: 25, Value: "10"}}
+	if len(items) != 2 || items[0] != want[0] || items[1] != want[1] {
+		t.Errorf("items: %+v", items)
+	}
+
+	// Sparklines get one line per label value unless the template lists them
+	d, _ = newPromDecoder(modules.PromScrape{Series: `queue_depth{queue!="sms"}`, GroupBy: "queue"},
+		protocol.RenderConfig{Type: protocol.TypeSpark})
+	data, _ = d.decode([]byte(promBody), time.Now())
+	series, _ := data.Props["series"].([]map[string]any)
+	if data.Value != 30.0 || len(series) != 1 || series[0]["key"] != "mail" {
+		t.Errorf("sparkline: %+v", data)
+	}
+
+	// No match is an empty reading, not an error
+	d, _ = newPromDecoder(modules.PromScrape{Series: "missing"}, protocol.RenderConfig{Type: protocol.TypeGauge})
+	if data, err := d.decode([]byte(promBody), time.Now()); err != nil || data.Value != 0.0 {
+		t.Errorf("want zero reading, got %+v, %v", data, err)
+	}
+	if _, err := d.decode([]byte("queue_depth{"), time.Now()); err == nil {
+		t.Error("want parse error")
+	}
+}
+
+func TestPromDecoder_Rate(t *testing.T) {
+	d, _ := newPromDecoder(modules.PromScrape{Series: `http_requests_total{job="api"}`, Rate: true, GroupBy: "code"},
+		protocol.RenderConfig{Type: protocol.TypeKeyValue})
+	body := func(ok, fail int) []byte {
+		return fmt.Appendf(nil, "http_requests_total{job=\"api\",code=\"200\"} %d\nhttp_requests_total{job=\"api\",code=\"500\"} %d\n", ok, fail)
+	}
+	t0 := time.Now()
+
+	if data, err := d.decode(body(100, 10), t0); data != nil || err != nil {
+		t.Fatalf("first scrape has no rate yet, got %+v, %v", data, err)
+	}
+	data, err := d.decode(body(300, 20), t0.Add(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Value != 21.0 || data.Series["200"] != 20 || data.Series["500"] != 1 {
+		t.Errorf("got %+v", data)
+	}
+	items := data.Items.([]protocol.KeyValueItem)
+	if len(items) != 2 || items[0] != (protocol.KeyValueItem{Key: "200", Value: "20/s"}) {
+		t.Errorf("items: %+v", items)
+	}
+
+	// A counter reset counts from zero instead of going negative
+	data, _ = d.decode(body(50, 25), t0.Add(20*time.Second))
+	if data.Series["200"] != 5 || data.Series["500"] != 0.5 {
+		t.Errorf("after reset: %+v", data.Series)
+	}
+}
+
+func TestPullSource_Prometheus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write([]byte(promBody))
+	}))
+	defer srv.Close()
+
+	cfg := modules.PullConfig{
+		ID:         "svc.queues",
+		URL:        srv.URL + "/metrics",
+		Template:   protocol.RenderConfig{Type: protocol.TypeGauge, Props: map[string]any{"max": 100.0}},
+		Prometheus: &modules.PromScrape{Series: `queue_depth{queue="mail"}`},
+	}
+	p, err := NewPullSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.Fetch(context.Background())
+	if err != nil || data.Value != 30.0 {
+		t.Fatalf("got %+v, %v", data, err)
+	}
+
+	for _, bad := range []*modules.PromScrape{{}, {Series: "x{a"}} {
+		cfg.Prometheus = bad
+		if _, err := NewPullSource(cfg); err == nil {
+			t.Errorf("want error for %+v", bad)
+		}
+	}
+	cfg.Prometheus = &modules.PromScrape{Series: "queue_depth"}
+	cfg.Fields = map[string]string{"value": "x"}
+	if _, err := NewPullSource(cfg); err == nil {
+		t.Error("fields and prometheus should be exclusive")
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultPullInterval = 10 * time.Second
+	defaultPullTimeout  = 5 * time.Second
+	maxPullBody         = 1 << 20
+)
+
+// PullSource polls a URL configured in AppConfig.Pulls: a JSON document, or a
+// Prometheus text endpoint. A SidecarSource holds the template, props and
+// offline flag, and results go through UpdateSidecarData, so a pulled widget
+// behaves exactly like a pushed one.
+type PullSource struct {
+	cfg      modules.PullConfig
+	decoder  pullDecoder
+	accept   string
+	interval time.Duration
+	timeout  time.Duration
+	client   *http.Client
+}
+
+// pullDecoder turns a response body into a payload. A nil payload without an
+// error means there is nothing to show yet, e.g. a rate's first scrape.
+type pullDecoder interface {
+	decode(body []byte, now time.Time) (*protocol.DataPayload, error)
+}
+
+// jsonDecoder maps a JSON body through the configured fields.
+type jsonDecoder struct {
+	mapping *modules.JSONMapping
+	typ     protocol.ComponentType
+}
+
+func (d jsonDecoder) decode(body []byte, _ time.Time) (*protocol.DataPayload, error) {
+	return d.mapping.Payload(body, d.typ)
+}
+
+// NewPullSource validates cfg and builds its poller.
+func NewPullSource(cfg modules.PullConfig) (*PullSource, error) {
+	if cfg.ID == "" {
+		return nil, errors.New("pull source: id required")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("pull %s: url must be http(s)", cfg.ID)
+	}
+	if cfg.Template.Type == "" {
+		return nil, fmt.Errorf("pull %s: template.type required", cfg.ID)
+	}
+	p := &PullSource{
+		cfg:      cfg,
+		interval: defaultPullInterval,
+		timeout:  defaultPullTimeout,
+		client:   &http.Client{},
+	}
+	if cfg.Interval != "" {
+		if p.interval, err = time.ParseDuration(cfg.Interval); err != nil || p.interval < time.Second {
+			return nil, fmt.Errorf("pull %s: interval must be a duration of at least 1s", cfg.ID)
+		}
+	}
+	if cfg.Timeout != "" {
+		if p.timeout, err = time.ParseDuration(cfg.Timeout); err != nil || p.timeout <= 0 {
+			return nil, fmt.Errorf("pull %s: invalid timeout %q", cfg.ID, cfg.Timeout)
+		}
+	}
+
+	if cfg.Prometheus != nil {
+		if len(cfg.Fields) > 0 {
+			return nil, fmt.Errorf("pull %s: fields and prometheus cannot both be set", cfg.ID)
+		}
+		if p.decoder, err = newPromDecoder(*cfg.Prometheus, cfg.Template); err != nil {
+			return nil, fmt.Errorf("pull %s: %w", cfg.ID, err)
+		}
+		p.accept = "text/plain;version=0.0.4"
+		return p, nil
+	}
+	mapping, err := modules.NewJSONMapping(cfg.Fields)
+	if err != nil {
+		return nil, fmt.Errorf("pull %s: %w", cfg.ID, err)
+	}
+	p.decoder, p.accept = jsonDecoder{mapping: mapping, typ: cfg.Template.Type}, "application/json"
+	return p, nil
+}
+
+// Fetch GETs the URL once and decodes the body into a payload.
+func (p *PullSource) Fetch(ctx context.Context) (*protocol.DataPayload, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", p.accept)
+	for k, v := range p.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%s returned %s", p.cfg.URL, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPullBody))
+	if err != nil {
+		return nil, err
+	}
+	return p.decoder.decode(body, time.Now())
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewPullSource_Validation(t *testing.T) {
+	tmpl := protocol.RenderConfig{Type: protocol.TypeSpark}
+	bad := []modules.PullConfig{
+		{URL: "http://x", Template: tmpl},
+		{ID: "a", URL: "ftp://x", Template: tmpl},
+		{ID: "a", URL: "http://x"},
+		{ID: "a", URL: "http://x", Template: tmpl, Interval: "10ms"},
+		{ID: "a", URL: "http://x", Template: tmpl, Fields: map[string]string{"colour": "c"}},
+		{ID: "a", URL: "http://x", Template: tmpl, Fields: map[string]string{"items.label": "name"}},
+	}
+	for _, cfg := range bad {
+		if _, err := NewPullSource(cfg); err == nil {
+			t.Errorf("want error for %+v", cfg)
+		}
+	}
+}
+
+func TestPullSource_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"green","jobs":{"pending":4},"queues":[{"name":"mail","fill":40},{"name":"sms","fill":5}]}`))
+	}))
+	defer srv.Close()
+
+	cfg := modules.PullConfig{
+		ID:       "ci.status",
+		URL:      srv.URL,
+		Headers:  map[string]string{"Authorization": "Bearer t0k"},
+		Template: protocol.RenderConfig{Type: protocol.TypeBarList, Title: "CI"},
+		Fields: map[string]string{
+			"value":         "jobs.pending",
+			"label":         "status",
+			"items":         "queues",
+			"items.label":   "name",
+			"items.percent": "fill",
+			"items.value":   "fill",
+		},
+	}
+	p, err := NewPullSource(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.Fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Value != 4.0 || data.Label != "green" {
+		t.Errorf("got %+v", data)
+	}
+	items := data.Items.([]protocol.BarListItem)
+	if len(items) != 2 || items[0] != (protocol.BarListItem{Label: "mail", Percent: 40, Value: "40"}) {
+		t.Errorf("items: %+v", items)
+	}
+
+	cfg.Headers = nil
+	p, _ = NewPullSource(cfg)
+	if _, err := p.Fetch(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("want status error, got %v", err)
+	}
+}
+
+func TestSystemService_PullSourceLifecycle(t *testing.T) {
+	up := true
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !up {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"value": 12}`))
+	}))
+	defer srv.Close()
+
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+	cfg := s.GetConfig()
+	cfg.Pulls = []modules.PullConfig{{
+		ID:       "svc.queue",
+		URL:      srv.URL,
+		Interval: "1h",
+		Template: protocol.RenderConfig{Type: protocol.TypeSpark, Title: "Queue"},
+		Schema:   []protocol.ConfigSchema{{Name: "warn", Label: "Warn", Type: protocol.ConfigNumber, Default: 10}},
+	}}
+	if err := s.configService.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	s.syncPullSourcesLocked(s.GetConfig())
+
+	sc, ok := s.sources["svc.queue"].(*SidecarSource)
+	if !ok || sc.puller == nil || sc.config.Title != "Queue" || sc.config.ID != "svc.queue" {
+		t.Fatalf("want pulled sidecar source, got %+v", s.sources["svc.queue"])
+	}
+	var widget *modules.WidgetConfig
+	for _, w := range s.GetConfig().Widgets {
+		if w.ID == "svc.queue" {
+			widget = &w
+		}
+	}
+	if widget == nil || !widget.Enabled || widget.SidecarType != "sparkline" || widget.Props["warn"] != 10 {
+		t.Fatalf("want persisted widget with schema defaults, got %+v", widget)
+	}
+
+	s.pullOnce("svc.queue", sc.puller)
+	if got := s.cache["svc.queue"]; got == nil || got.Value != 12.0 {
+		t.Fatalf("want pulled value cached, got %+v", got)
+	}
+
+	// Unreachable endpoint goes offline at once, and back online on recovery
+	up = false
+	s.pullOnce("svc.queue", sc.puller)
+	if !sc.isOffline || s.cache["svc.queue"].Props["isOffline"] != true {
+		t.Error("failed pull should mark the source offline")
+	}
+	up = true
+	s.pullOnce("svc.queue", sc.puller)
+	if sc.isOffline {
+		t.Error("successful pull should bring the source back online")
+	}
+
+	if err := s.RemoveSidecar("svc.queue"); err == nil {
+		t.Error("pulled sources should only be removable from config")
+	}
+
+	cfg = s.GetConfig()
+	cfg.Pulls = nil
+	s.syncPullSourcesLocked(cfg)
+	if _, ok := s.sources["svc.queue"]; ok {
+		t.Error("removed pull entry should drop its source")
+	}
+	for _, w := range s.GetConfig().Widgets {
+		if w.ID == "svc.queue" {
+			t.Error("removed pull entry should drop its widget")
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"glancehud/internal/protocol"
+	"log/slog"
+)
+
+// sidecarConnBuffer is the number of outbound frames queued per connection
+// before further pushes are dropped.
+const sidecarConnBuffer = 16
+
+// sidecarConn is the server side of a long-lived WebSocket sidecar connection.
+// A single connection may carry several module IDs (e.g. one per GPU).
+type sidecarConn struct {
+	out chan protocol.SidecarResponse
+	ids map[string]struct{} // module IDs bound to this conn; guarded by SystemService.mu
+}
+
+func newSidecarConn() *sidecarConn {
+	return &sidecarConn{
+		out: make(chan protocol.SidecarResponse, sidecarConnBuffer),
+		ids: make(map[string]struct{}),
+	}
+}
+
+// send queues a frame for the writer goroutine without blocking. Callers may
+// hold SystemService.mu, so a stalled socket must never back-pressure them.
+func (c *sidecarConn) send(resp protocol.SidecarResponse) {
+	select {
+	case c.out <- resp:
+	default:
+		slog.Warn("Sidecar socket send buffer full, dropping frame", "moduleId", resp.ModuleID)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"glancehud/internal/statsd"
+	"log/slog"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	defaultStatsDPort   = 8125
+	defaultStatsDFlush  = 5 * time.Second
+	defaultStatsDPrefix = "statsd."
+	maxStatsDPacket     = 64 << 10
+)
+
+// StatsDListener receives StatsD metrics over UDP and, every flush interval,
+// pushes their aggregates into sidecar widgets. Widgets are registered lazily
+// through RegisterSidecar with a sparkline template, so they persist, restore
+// and go offline like any pushed sidecar.
+type StatsDListener struct {
+	system *SystemService
+	agg    *statsd.Aggregator
+	port   int
+	flush  time.Duration
+	prefix string
+	depth  int
+}
+
+// NewStatsDListener validates cfg and applies its defaults.
+func NewStatsDListener(s *SystemService, cfg modules.StatsDConfig) (*StatsDListener, error) {
+	l := &StatsDListener{
+		system: s,
+		agg:    statsd.NewAggregator(),
+		port:   defaultStatsDPort,
+		flush:  defaultStatsDFlush,
+		prefix: defaultStatsDPrefix,
+		depth:  cfg.GroupDepth,
+	}
+	if cfg.Port != 0 {
+		if cfg.Port < 0 || cfg.Port > 65535 {
+			return nil, fmt.Errorf("statsd: invalid port %d", cfg.Port)
+		}
+		l.port = cfg.Port
+	}
+	if cfg.Flush != "" {
+		d, err := time.ParseDuration(cfg.Flush)
+		if err != nil || d < time.Second || d >= SidecarTTL {
+			return nil, fmt.Errorf("statsd: flush must be a duration between 1s and %s", SidecarTTL)
+		}
+		l.flush = d
+	}
+	if cfg.Prefix != "" {
+		l.prefix = cfg.Prefix
+	}
+	if l.depth < 0 {
+		return nil, errors.New("statsd: groupDepth must not be negative")
+	}
+	return l, nil
+}
+
+// Serve reads packets from conn until it is closed.
+func (l *StatsDListener) Serve(conn net.PacketConn) {
+	buf := make([]byte, maxStatsDPacket)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			slog.Warn("StatsD read failed", "error", err)
+			continue
+		}
+		l.ingest(buf[:n])
+	}
+}
+
+func (l *StatsDListener) ingest(packet []byte) {
+	metrics, err := statsd.ParsePacket(packet)
+	if err != nil {
+		slog.Debug("StatsD lines dropped", "error", err)
+	}
+	for _, m := range metrics {
+		if !l.agg.Add(m) {
+			slog.Debug("StatsD metric dropped", "name", m.Name, "kind", m.Kind)
+		}
+	}
+}
+
+// runFlush flushes the aggregator on every interval, forever.
+func (l *StatsDListener) runFlush() {
+	ticker := time.NewTicker(l.flush)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.flushOnce()
+	}
+}
+
+// flushOnce turns the interval's aggregates into one update per widget.
+// Widgets whose metrics all went without samples are left alone, so they go
+// offline and, once removed by the user, stay removed.
+func (l *StatsDListener) flushOnce() {
+	groups := make(map[string][]statsd.Stat)
+	fresh := make(map[string]bool)
+	var ids []string
+	for _, st := range l.agg.Flush(l.flush) {
+		id := l.widgetID(st.Name)
+		if _, ok := groups[id]; !ok {
+			ids = append(ids, id)
+		}
+		groups[id] = append(groups[id], st)
+		fresh[id] = fresh[id] || st.Fresh
+	}
+	for _, id := range ids {
+		if !fresh[id] {
+			continue
+		}
+		l.ensureWidget(id, groups[id][0].Kind)
+		l.system.updateIngestedData(id, l.payload(id, groups[id]))
+	}
+}
+
+// widgetID maps a metric name to its widget: the whole name, or with
+// GroupDepth N its first N dot-separated segments.
+func (l *StatsDListener) widgetID(name string) string {
+	if l.depth > 0 {
+		if segs := strings.SplitN(name, ".", l.depth+1); len(segs) > l.depth {
+			name = strings.Join(segs[:l.depth], ".")
+		}
+	}
+	return l.prefix + name
+}
+
+// ensureWidget registers a sparkline sidecar for id unless a source exists,
+// e.g. one restored from config or removed and now reappearing.
+func (l *StatsDListener) ensureWidget(id string, kind statsd.Kind) {
+	l.system.mu.RLock()
+	_, exists := l.system.sources[id]
+	l.system.mu.RUnlock()
+	if exists {
+		return
+	}
+	// Rates, gauges and timings are not percentages: use a fixed line color
+	// instead of the renderer's percent thresholds.
+	tmpl := protocol.RenderConfig{
+		Type:  protocol.TypeSpark,
+		Title: strings.TrimPrefix(id, l.prefix),
+		Props: map[string]any{"color": modules.NeutralColor},
+	}
+	switch kind {
+	case statsd.Counter:
+		tmpl.Props["unit"] = "/s"
+	case statsd.Timer:
+		tmpl.Props["unit"] = " ms"
+	}
+	l.system.RegisterSidecar(id, &tmpl, nil)
+}
+
+// payload builds one widget's data. A single metric reports its own value,
+// with timer and counter details in Series. A group reports the sum, with
+// one sparkline line per metric keyed by the rest of its name.
+func (l *StatsDListener) payload(id string, stats []statsd.Stat) *protocol.DataPayload {
+	key := strings.TrimPrefix(id, l.prefix)
+	if len(stats) == 1 && stats[0].Name == key {
+		st := stats[0]
+		p := &protocol.DataPayload{Value: round3(st.Value)}
+		if len(st.Extra) > 0 {
+			p.Series = make(map[string]float64, len(st.Extra))
+			for k, v := range st.Extra {
+				p.Series[k] = round3(v)
+			}
+		}
+		return p
+	}
+
+	total := 0.0
+	p := &protocol.DataPayload{Series: make(map[string]float64, len(stats))}
+	names := make([]string, 0, len(stats))
+	for _, st := range stats {
+		name := strings.TrimPrefix(st.Name, key+".")
+		total += st.Value
+		p.Series[name] = round3(st.Value)
+		names = append(names, name)
+	}
+	p.Value = round3(total)
+	p.Props = map[string]any{"series": seriesLines(names)}
+	return p
+}
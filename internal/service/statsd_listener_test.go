@@ -0,0 +1,128 @@
+package service
+
+import (
+	"glancehud/internal/modules"
+	"glancehud/internal/protocol"
+	"net"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNewStatsDListener_Validation(t *testing.T) {
+	s := newTestService(t)
+	l, err := NewStatsDListener(s, modules.StatsDConfig{})
+	if err != nil || l.port != 8125 || l.flush != 5*time.Second || l.prefix != "statsd." {
+		t.Fatalf("defaults: %+v, %v", l, err)
+	}
+	for _, cfg := range []modules.StatsDConfig{
+		{Port: 70000},
+		{Flush: "100ms"},
+		{Flush: SidecarTTL.String()},
+		{GroupDepth: -1},
+	} {
+		if _, err := NewStatsDListener(s, cfg); err == nil {
+			t.Errorf("want error for %+v", cfg)
+		}
+	}
+}
+
+func TestStatsDListener_Flush(t *testing.T) {
+	s := newTestService(t)
+	l, _ := NewStatsDListener(s, modules.StatsDConfig{Flush: "2s", GroupDepth: 1})
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp unavailable: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		l.Serve(conn)
+		close(done)
+	}()
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Write([]byte("api.hits:10|c\napi.errors:2|c\nqueue:7|g\nbogus\n"))
+	client.Close()
+
+	// UDP delivery is asynchronous: wait until the packet is aggregated
+	for i := 0; i < 100 && len(l.agg.Flush(time.Second)) < 3; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	conn.Close()
+	<-done
+	// Re-add after the probing flushes above consumed the counters
+	l.ingest([]byte("api.hits:10|c\napi.errors:2|c\nqueue:7|g"))
+	l.flushOnce()
+
+	api, ok := s.sources["statsd.api"].(*SidecarSource)
+	if !ok || api.config.Type != protocol.TypeSpark || api.config.Props["unit"] != "/s" ||
+		api.config.Props["color"] != modules.NeutralColor {
+		t.Fatalf("want registered sparkline sidecar, got %+v", s.sources["statsd.api"])
+	}
+	data := s.cache["statsd.api"]
+	if data == nil || data.Value != 6.0 || data.Series["hits"] != 5 || data.Series["errors"] != 1 {
+		t.Fatalf("grouped payload: %+v", data)
+	}
+	if series, _ := data.Props["series"].([]map[string]any); len(series) != 2 || series[0]["key"] != "errors" {
+		t.Errorf("series props: %+v", data.Props)
+	}
+	if got := s.cache["statsd.queue"]; got == nil || got.Value != 7.0 {
+		t.Errorf("gauge payload: %+v", got)
+	}
+
+	// Registration persists the widgets like any lazily registered sidecar
+	var ids []string
+	for i := 0; i < 100; i++ {
+		ids = ids[:0]
+		for _, w := range s.GetConfig().Widgets {
+			if w.SidecarType == "sparkline" {
+				ids = append(ids, w.ID)
+			}
+		}
+		if len(ids) == 2 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []string{"statsd.api", "statsd.queue"}) {
+		t.Errorf("persisted widgets: %v", ids)
+	}
+}
+
+func TestStatsDListener_RemovedWidgetStaysRemoved(t *testing.T) {
+	s := newTestService(t)
+	l, _ := NewStatsDListener(s, modules.StatsDConfig{})
+
+	l.ingest([]byte("jobs:3|g"))
+	l.flushOnce()
+	if _, ok := s.sources["statsd.jobs"]; !ok {
+		t.Fatal("want statsd.jobs registered")
+	}
+	// Let the async config write land before removing
+	for i := 0; i < 100 && len(s.GetConfig().Widgets) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := s.RemoveSidecar("statsd.jobs"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Idle flush: the gauge repeats its value but must not re-register
+	l.flushOnce()
+	if _, ok := s.sources["statsd.jobs"]; ok {
+		t.Error("idle metric re-registered a removed widget")
+	}
+
+	// New samples bring it back
+	l.ingest([]byte("jobs:4|g"))
+	l.flushOnce()
+	if _, ok := s.sources["statsd.jobs"]; !ok {
+		t.Error("want statsd.jobs re-registered after new samples")
+	}
+	for i := 0; i < 100 && len(s.GetConfig().Widgets) == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -1,42 +1,67 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"glancehud/internal/alert"
+	"glancehud/internal/history"
 	"glancehud/internal/modules"
+	"glancehud/internal/notify"
 	"glancehud/internal/protocol"
+	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"time"
-
-	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 // SidecarTTL is the duration after which a sidecar widget is marked offline
 // if no data push is received.
 const SidecarTTL = 10 * time.Second
 
+// HistoryFlushInterval is how often recorded history is written to disk when
+// AppConfig.PersistHistory is enabled.
+const HistoryFlushInterval = time.Minute
+
 type SystemService struct {
-	app           *application.App
+	sink          EventSink
 	configService *modules.ConfigService
 	sources       map[string]WidgetSource // unified: native modules + sidecars
 	stopChans     map[string]chan struct{}
 	cache         map[string]*protocol.DataPayload
+	updates       updateHub // fan-out to external stream consumers
+	history       *history.Store
+	historyPath   string
+	alerts        *alert.Engine
+	notifier      *notify.Dispatcher
+	hostOverrides modules.HostPaths   // from the command line; beat env and config
+	influxIDs     map[string]struct{} // widgets registered by line-protocol writes
+	influxMu      sync.Mutex          // serialises influx widget registration
 	mu            sync.RWMutex
 }
 
 func NewSystemService() *SystemService {
 	mods := map[string]modules.Module{
-		"cpu":  modules.NewCPUModule(),
-		"mem":  modules.NewMemModule(),
-		"disk": modules.NewDiskModule(""),
-		"net":  modules.NewNetModule(),
+		"cpu":      modules.NewCPUModule(),
+		"mem":      modules.NewMemModule(),
+		"disk":     modules.NewDiskModule(""),
+		"net":      modules.NewNetModule(),
+		"proc":     modules.NewProcessModule(),
+		"diskio":   modules.NewDiskIOModule(),
+		"sensors":  modules.NewSensorsModule(),
+		"cgroup":   modules.NewCgroupModule(),
+		"watchdog": modules.NewWatchdogModule(),
+		"ports":    modules.NewPortsModule(),
+		"health":   modules.NewHealthModule(),
 	}
 
 	configDir := resolveConfigDir()
 	cs, _ := modules.NewConfigService(configDir, mods)
+	appConfig := cs.GetConfig()
 
 	sources := make(map[string]WidgetSource, len(mods))
 	for id, mod := range mods {
@@ -48,11 +73,22 @@ func NewSystemService() *SystemService {
 		sources:       sources,
 		stopChans:     make(map[string]chan struct{}),
 		cache:         make(map[string]*protocol.DataPayload),
+		history:       history.NewStore(appConfig.HistorySize),
+		historyPath:   filepath.Join(configDir, "history.json"),
+		alerts:        alert.NewEngine(),
+		notifier:      notify.NewDispatcher(),
+		influxIDs:     make(map[string]struct{}),
+	}
+
+	if appConfig.PersistHistory {
+		if err := s.history.Load(s.historyPath); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("Failed to load history", "path", s.historyPath, "error", err)
+		}
 	}
 
 	// Restore offline sidecar sources from persisted config so widgets remain
 	// visible (as offline) on restart before the sidecar process re-registers.
-	for _, wc := range cs.GetConfig().Widgets {
+	for _, wc := range appConfig.Widgets {
 		if _, isNative := mods[wc.ID]; isNative {
 			continue
 		}
@@ -72,15 +108,65 @@ func NewSystemService() *SystemService {
 	}
 
 	go s.runTTLChecker()
+	go s.runHistoryFlusher()
 
 	return s
 }
 
-func (s *SystemService) Start(app *application.App) {
-	s.app = app
+// SetHostOverrides sets host paths that take precedence over both the HOST_*
+// environment variables and AppConfig.Host. Call it before Start.
+func (s *SystemService) SetHostOverrides(p modules.HostPaths) {
+	s.mu.Lock()
+	s.hostOverrides = p
+	s.mu.Unlock()
+}
+
+// Start attaches the event sink and begins polling native modules.
+// sink may be nil, in which case events are silently dropped. If the sink also
+// implements DesktopNotifier, "desktop" alert channels are routed to it.
+func (s *SystemService) Start(sink EventSink) {
+	s.mu.Lock()
+	s.sink = sink
+	s.mu.Unlock()
+	if dn, ok := sink.(DesktopNotifier); ok {
+		s.notifier.SetDesktop(dn.Notify)
+	} else {
+		s.notifier.SetDesktop(nil)
+	}
 	s.StartMonitoring()
 }
 
+// emit forwards an event to the attached sink, if any.
+func (s *SystemService) emit(name string, data any) {
+	s.mu.RLock()
+	sink := s.sink
+	s.mu.RUnlock()
+	if sink != nil {
+		sink.Emit(name, data)
+	}
+}
+
+// emitUpdate records the value in history and publishes the update to the UI
+// sink and to every external stream subscriber.
+func (s *SystemService) emitUpdate(ev protocol.UpdateEvent) {
+	s.recordHistory(ev.ID, ev.Data)
+	s.updates.publish(ev)
+	s.emit("stats:update", ev)
+}
+
+// recordHistory appends the payload's value to the widget's history ring.
+func (s *SystemService) recordHistory(id string, data *protocol.DataPayload) {
+	if v, ok := historyValue(data); ok {
+		s.history.Record(id, time.Now(), v)
+	}
+}
+
+// subscribeUpdates registers an external consumer of widget updates.
+// The caller must invoke the returned cancel func when done.
+func (s *SystemService) subscribeUpdates() (<-chan protocol.UpdateEvent, func()) {
+	return s.updates.subscribe()
+}
+
 // RegisterSidecar handles lazy registration of sidecar widgets.
 // Native modules take precedence: if a native module with the same ID already
 // exists, this call is silently ignored.
@@ -132,8 +218,8 @@ func (s *SystemService) RegisterSidecar(id string, config *protocol.RenderConfig
 	// Notify frontend only when a source gains a valid template for the first time.
 	// Pure data-only pushes carry no render info; skipping the reload prevents both
 	// wasted round-trips and the "Unknown Widget Type" flash.
-	if gainsTemplate && s.app != nil {
-		s.app.Event.Emit("config:reload", nil)
+	if gainsTemplate {
+		s.emit("config:reload", nil)
 	}
 
 	if config != nil {
@@ -158,23 +244,10 @@ func (s *SystemService) ensureSidecarInConfig(id string, tmpl protocol.RenderCon
 		}
 	}
 
-	// Initialise Props from schema defaults so the sidecar receives meaningful
-	// values on the very first push, without requiring the user to open Settings.
-	// Layer order: schema defaults (base) → tmpl.Props (render overrides on top).
-	props := make(map[string]interface{})
-	for _, field := range schema {
-		if field.Name != "" && field.Default != nil {
-			props[field.Name] = field.Default
-		}
-	}
-	for k, v := range tmpl.Props {
-		props[k] = v
-	}
-
 	newWidget := modules.WidgetConfig{
 		ID:           id,
 		Enabled:      true,
-		Props:        props,
+		Props:        schemaDefaults(schema, tmpl.Props),
 		SidecarType:  string(tmpl.Type),
 		SidecarTitle: tmpl.Title,
 	}
@@ -186,6 +259,37 @@ func (s *SystemService) ensureSidecarInConfig(id string, tmpl protocol.RenderCon
 	slog.Info("Detected new sidecar, added to config", "id", id)
 }
 
+// schemaDefaults initialises widget props from schema defaults so a sidecar
+// receives meaningful values on the very first push, without requiring the
+// user to open Settings. Layer order: schema defaults (base) → template props
+// (render overrides on top).
+func schemaDefaults(schema []protocol.ConfigSchema, tmplProps map[string]any) map[string]interface{} {
+	props := make(map[string]interface{})
+	for _, field := range schema {
+		if field.Name != "" && field.Default != nil {
+			props[field.Name] = field.Default
+		}
+	}
+	for k, v := range tmplProps {
+		props[k] = v
+	}
+	return props
+}
+
+// seriesLines builds props.series for a multi-series sparkline whose keys are
+// only known at runtime, colouring the lines from modules.SeriesPalette.
+func seriesLines(keys []string) []map[string]any {
+	series := make([]map[string]any, 0, len(keys))
+	for i, k := range keys {
+		series = append(series, map[string]any{
+			"key":   k,
+			"label": k,
+			"color": modules.SeriesPalette[i%len(modules.SeriesPalette)],
+		})
+	}
+	return series
+}
+
 // UpdateSidecarData updates data for a sidecar source and returns the current
 // merged props (so the sidecar can read back settings set by the user).
 func (s *SystemService) UpdateSidecarData(id string, data *protocol.DataPayload) map[string]interface{} {
@@ -210,16 +314,28 @@ func (s *SystemService) UpdateSidecarData(id string, data *protocol.DataPayload)
 	props := sc.currentProps
 	s.mu.Unlock()
 
-	if s.app != nil {
-		s.app.Event.Emit("stats:update", protocol.UpdateEvent{
-			ID:   id,
-			Data: data,
-		})
-	}
+	s.evaluateAlerts(id, data)
+
+	s.emitUpdate(protocol.UpdateEvent{
+		ID:   id,
+		Data: data,
+	})
 
 	return props
 }
 
+// updateIngestedData is UpdateSidecarData for sources fed by the StatsD
+// listener or line-protocol writes, which have no sidecar to deliver
+// actions to.
+func (s *SystemService) updateIngestedData(id string, data *protocol.DataPayload) map[string]interface{} {
+	s.mu.Lock()
+	if sc, ok := s.sources[id].(*SidecarSource); ok {
+		sc.ingested = true
+	}
+	s.mu.Unlock()
+	return s.UpdateSidecarData(id, data)
+}
+
 func (s *SystemService) runTTLChecker() {
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
@@ -231,8 +347,8 @@ func (s *SystemService) runTTLChecker() {
 
 func (s *SystemService) checkSidecarTTL() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
+	var events []protocol.UpdateEvent
 	now := time.Now()
 	for id, src := range s.sources {
 		sc, ok := src.(*SidecarSource)
@@ -240,39 +356,98 @@ func (s *SystemService) checkSidecarTTL() {
 			continue
 		}
 
+		// Live WebSocket sidecars are tracked by the connection itself, and
+		// pulled sources by their poller.
+		if sc.conn != nil || sc.puller != nil {
+			continue
+		}
+
 		if !sc.isOffline && now.Sub(sc.lastSeen) > SidecarTTL {
-			sc.isOffline = true
-
-			// Deep copy to avoid mutating sc.currentData.Props via shared map reference
-			offlineData := &protocol.DataPayload{}
-			if sc.currentData != nil {
-				*offlineData = *sc.currentData
-				if sc.currentData.Props != nil {
-					propsCopy := make(map[string]any, len(sc.currentData.Props))
-					for k, v := range sc.currentData.Props {
-						propsCopy[k] = v
-					}
-					offlineData.Props = propsCopy
-				} else {
-					offlineData.Props = nil
-				}
-			}
-			if offlineData.Props == nil {
-				offlineData.Props = make(map[string]any)
-			}
-			offlineData.Props["isOffline"] = true
+			events = append(events, s.markSidecarOfflineLocked(id, sc))
+			slog.Warn("Sidecar timed out, marking offline", "id", id)
+		}
+	}
+	s.mu.Unlock()
 
-			s.cache[id] = offlineData
+	// Emit after releasing the lock so a slow sink cannot stall other writers.
+	for _, ev := range events {
+		s.emitUpdate(ev)
+	}
+}
 
-			if s.app != nil {
-				s.app.Event.Emit("stats:update", protocol.UpdateEvent{
-					ID:   id,
-					Data: offlineData,
-				})
+// markSidecarOfflineLocked flags sc as offline, caches an offline payload and
+// returns the update event to emit. Caller must hold s.mu write lock.
+func (s *SystemService) markSidecarOfflineLocked(id string, sc *SidecarSource) protocol.UpdateEvent {
+	sc.isOffline = true
+
+	// Deep copy to avoid mutating sc.currentData.Props via shared map reference
+	offlineData := &protocol.DataPayload{}
+	if sc.currentData != nil {
+		*offlineData = *sc.currentData
+		if sc.currentData.Props != nil {
+			propsCopy := make(map[string]any, len(sc.currentData.Props))
+			for k, v := range sc.currentData.Props {
+				propsCopy[k] = v
 			}
-			slog.Warn("Sidecar timed out, marking offline", "id", id)
+			offlineData.Props = propsCopy
+		} else {
+			offlineData.Props = nil
 		}
 	}
+	if offlineData.Props == nil {
+		offlineData.Props = make(map[string]any)
+	}
+	offlineData.Props["isOffline"] = true
+
+	s.cache[id] = offlineData
+
+	return protocol.UpdateEvent{
+		ID:   id,
+		Data: offlineData,
+	}
+}
+
+// attachSidecarConn binds a WebSocket connection to a registered sidecar source
+// so settings changes are pushed as soon as ApplyConfig runs. It returns the
+// current props and whether this call created the binding.
+func (s *SystemService) attachSidecarConn(id string, conn *sidecarConn) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sc, ok := s.sources[id].(*SidecarSource)
+	if !ok {
+		return nil, false
+	}
+	if sc.conn == conn {
+		return sc.currentProps, false
+	}
+	sc.conn = conn
+	conn.ids[id] = struct{}{}
+	return sc.currentProps, true
+}
+
+// detachSidecarConn unbinds every source served by conn and marks them offline
+// immediately, instead of waiting for SidecarTTL.
+func (s *SystemService) detachSidecarConn(conn *sidecarConn) {
+	s.mu.Lock()
+
+	var events []protocol.UpdateEvent
+	for id := range conn.ids {
+		sc, ok := s.sources[id].(*SidecarSource)
+		if !ok || sc.conn != conn {
+			continue
+		}
+		sc.conn = nil
+		if !sc.isOffline {
+			events = append(events, s.markSidecarOfflineLocked(id, sc))
+			slog.Info("Sidecar socket closed, marking offline", "id", id)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, ev := range events {
+		s.emitUpdate(ev)
+	}
 }
 
 func (s *SystemService) GetConfig() modules.AppConfig {
@@ -302,6 +477,12 @@ type monitorTask struct {
 	stop     chan struct{}
 }
 
+type pullTask struct {
+	id   string
+	src  *PullSource
+	stop chan struct{}
+}
+
 func (s *SystemService) StartMonitoring() {
 	// Phase 1: synchronous state mutation under lock
 	s.mu.Lock()
@@ -314,7 +495,14 @@ func (s *SystemService) StartMonitoring() {
 	s.cache = make(map[string]*protocol.DataPayload)
 	config := s.configService.GetConfig()
 
+	// Precedence: command line > HOST_* env > config.json
+	modules.SetHostPaths(config.Host.Merge(modules.HostPathsFromEnv()).Merge(s.hostOverrides))
+
+	config = s.syncExecSourcesLocked(config)
+	config = s.syncPullSourcesLocked(config)
+
 	var tasks []monitorTask
+	var pulls []pullTask
 	for _, widgetCfg := range config.Widgets {
 		if !widgetCfg.Enabled {
 			continue
@@ -334,7 +522,7 @@ func (s *SystemService) StartMonitoring() {
 		// ApplyConfig for all sources (native + sidecar)
 		src.ApplyConfig(mergedProps)
 
-		// Only native modules need a goroutine ticker
+		// Native modules and pulled sources need a goroutine ticker
 		if puller, ok := src.(modules.Module); ok {
 			stop := make(chan struct{})
 			s.stopChans[widgetCfg.ID] = stop
@@ -343,28 +531,228 @@ func (s *SystemService) StartMonitoring() {
 				renderID: puller.GetRenderConfig().ID,
 				stop:     stop,
 			})
+		} else if sc, ok := src.(*SidecarSource); ok && sc.puller != nil {
+			stop := make(chan struct{})
+			s.stopChans[widgetCfg.ID] = stop
+			pulls = append(pulls, pullTask{id: widgetCfg.ID, src: sc.puller, stop: stop})
 		}
 	}
 
+	s.applyAlertRulesLocked(config.Alerts)
+
 	s.mu.Unlock()
 
+	for _, err := range s.notifier.SetChannels(config.Notifiers) {
+		slog.Warn("Skipping invalid notifier", "error", err)
+	}
+
 	// Phase 2: launch goroutines after lock is released
 	for _, t := range tasks {
 		go s.runMonitor(t.mod, t.renderID, t.stop)
 	}
+	for _, t := range pulls {
+		go s.runPull(t.id, t.src, t.stop)
+	}
+}
+
+// syncExecSourcesLocked builds one source per config.Exec entry, reusing
+// modules whose entry is unchanged so their last data survives a settings
+// save. config.Widgets is kept in step: new entries get an enabled widget and
+// entries removed from config.Exec lose theirs. Invalid entries are logged and
+// skipped but keep their widget (and layout) until removed. Caller must hold
+// s.mu.
+func (s *SystemService) syncExecSourcesLocked(config modules.AppConfig) modules.AppConfig {
+	listed := make(map[string]bool, len(config.Exec))
+	for _, ec := range config.Exec {
+		id := modules.ExecWidgetID(ec.Name)
+		if listed[id] {
+			slog.Warn("Skipping duplicate exec widget", "name", ec.Name)
+			continue
+		}
+		listed[id] = true
+
+		if cur, ok := s.sources[id].(*modules.ExecModule); ok && reflect.DeepEqual(cur.Config(), ec) {
+			continue
+		}
+		mod, err := modules.NewExecModule(ec)
+		if err != nil {
+			slog.Warn("Skipping invalid exec widget", "name", ec.Name, "error", err)
+			delete(s.sources, id)
+			continue
+		}
+		s.sources[id] = mod
+	}
+	for id, src := range s.sources {
+		if _, ok := src.(*modules.ExecModule); ok && !listed[id] {
+			delete(s.sources, id)
+			s.history.Remove(src.GetRenderConfig().ID)
+		}
+	}
+
+	changed := false
+	present := make(map[string]bool, len(config.Widgets))
+	widgets := make([]modules.WidgetConfig, 0, len(config.Widgets)+len(config.Exec))
+	for _, w := range config.Widgets {
+		if strings.HasPrefix(w.ID, modules.ExecWidgetID("")) && w.SidecarType == "" && !listed[w.ID] {
+			changed = true
+			continue
+		}
+		present[w.ID] = true
+		widgets = append(widgets, w)
+	}
+	for _, ec := range config.Exec {
+		id := modules.ExecWidgetID(ec.Name)
+		if !present[id] {
+			present[id] = true
+			widgets = append(widgets, modules.WidgetConfig{ID: id, Enabled: true})
+			changed = true
+		}
+	}
+	if changed {
+		config.Widgets = widgets
+		if err := s.configService.UpdateConfig(config); err != nil {
+			slog.Error("Failed to save exec widgets", "error", err)
+		}
+	}
+	return config
+}
+
+// syncPullSourcesLocked registers a sidecar source for every config.Pulls
+// entry, keeping the poller of unchanged entries. A native module with the
+// same ID wins, as with RegisterSidecar. Entries removed from config.Pulls
+// lose their source and widget. Caller must hold s.mu.
+func (s *SystemService) syncPullSourcesLocked(config modules.AppConfig) modules.AppConfig {
+	listed := make(map[string]bool, len(config.Pulls))
+	for _, pc := range config.Pulls {
+		if listed[pc.ID] {
+			slog.Warn("Skipping duplicate pull source", "id", pc.ID)
+			continue
+		}
+		listed[pc.ID] = true
+
+		if _, isNative := s.sources[pc.ID].(modules.Module); isNative {
+			slog.Warn("Ignoring pull source: native module with same ID exists", "id", pc.ID)
+			continue
+		}
+		sc, exists := s.sources[pc.ID].(*SidecarSource)
+		if exists && sc.puller != nil && reflect.DeepEqual(sc.puller.cfg, pc) {
+			continue
+		}
+		ps, err := NewPullSource(pc)
+		if err != nil {
+			slog.Warn("Skipping invalid pull source", "id", pc.ID, "error", err)
+			if exists {
+				sc.puller = nil // falls back to sidecar TTL and goes offline
+			}
+			continue
+		}
+		if !exists {
+			sc = &SidecarSource{id: pc.ID, lastSeen: time.Now()}
+			s.sources[pc.ID] = sc
+		}
+		sc.puller = ps
+		sc.updateTemplate(pc.Template, pc.Schema)
+	}
+
+	removed := make(map[string]bool)
+	for id, src := range s.sources {
+		if sc, ok := src.(*SidecarSource); ok && sc.puller != nil && !listed[id] {
+			delete(s.sources, id)
+			delete(s.cache, id)
+			s.history.Remove(id)
+			removed[id] = true
+		}
+	}
+
+	changed := false
+	widgets := make([]modules.WidgetConfig, 0, len(config.Widgets)+len(config.Pulls))
+	present := make(map[string]bool, len(config.Widgets))
+	for _, w := range config.Widgets {
+		if removed[w.ID] {
+			changed = true
+			continue
+		}
+		if sc, ok := s.sources[w.ID].(*SidecarSource); ok && sc.puller != nil &&
+			(w.SidecarType != string(sc.config.Type) || w.SidecarTitle != sc.config.Title) {
+			// Persist the template so the widget is restored offline on restart
+			w.SidecarType, w.SidecarTitle = string(sc.config.Type), sc.config.Title
+			changed = true
+		}
+		present[w.ID] = true
+		widgets = append(widgets, w)
+	}
+	for _, pc := range config.Pulls {
+		sc, ok := s.sources[pc.ID].(*SidecarSource)
+		if !ok || sc.puller == nil || present[pc.ID] {
+			continue
+		}
+		present[pc.ID] = true
+		widgets = append(widgets, modules.WidgetConfig{
+			ID:           pc.ID,
+			Enabled:      true,
+			Props:        schemaDefaults(pc.Schema, pc.Template.Props),
+			SidecarType:  string(sc.config.Type),
+			SidecarTitle: sc.config.Title,
+		})
+		changed = true
+	}
+	if changed {
+		config.Widgets = widgets
+		if err := s.configService.UpdateConfig(config); err != nil {
+			slog.Error("Failed to save pull widgets", "error", err)
+		}
+	}
+	return config
+}
+
+// runPull polls a pulled source until stopChan is closed. A failed fetch
+// marks the source offline at once rather than waiting for SidecarTTL.
+func (s *SystemService) runPull(id string, p *PullSource, stopChan chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		s.pullOnce(id, p)
+		select {
+		case <-stopChan:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SystemService) pullOnce(id string, p *PullSource) {
+	data, err := p.Fetch(context.Background())
+	if err == nil {
+		if data != nil {
+			s.UpdateSidecarData(id, data)
+		}
+		return
+	}
+
+	s.mu.Lock()
+	sc, ok := s.sources[id].(*SidecarSource)
+	if !ok || sc.puller != p || sc.isOffline {
+		s.mu.Unlock()
+		return
+	}
+	ev := s.markSidecarOfflineLocked(id, sc)
+	s.mu.Unlock()
+
+	slog.Warn("Pull source failed, marking offline", "id", id, "error", err)
+	s.emitUpdate(ev)
 }
 
 func (s *SystemService) runMonitor(m modules.Module, eventID string, stopChan chan struct{}) {
 	if data, err := m.Update(); err == nil {
+		s.evaluateAlerts(eventID, data)
 		s.mu.Lock()
 		s.cache[eventID] = data
 		s.mu.Unlock()
-		if s.app != nil {
-			s.app.Event.Emit("stats:update", protocol.UpdateEvent{
-				ID:   eventID,
-				Data: data,
-			})
-		}
+		s.emitUpdate(protocol.UpdateEvent{
+			ID:   eventID,
+			Data: data,
+		})
 	}
 
 	ticker := time.NewTicker(m.Interval())
@@ -380,12 +768,18 @@ func (s *SystemService) runMonitor(m modules.Module, eventID string, stopChan ch
 				continue
 			}
 
+			// Evaluate on every tick, not just on change, so "for" durations
+			// keep advancing while the value holds steady.
+			s.evaluateAlerts(eventID, data)
+
 			s.mu.RLock()
 			lastData, ok := s.cache[eventID]
 			unchanged := ok && reflect.DeepEqual(lastData, data)
 			s.mu.RUnlock()
 
 			if unchanged {
+				// Still sample history so steady values leave no gaps.
+				s.recordHistory(eventID, data)
 				continue
 			}
 
@@ -393,7 +787,7 @@ func (s *SystemService) runMonitor(m modules.Module, eventID string, stopChan ch
 			s.cache[eventID] = data
 			s.mu.Unlock()
 
-			s.app.Event.Emit("stats:update", protocol.UpdateEvent{
+			s.emitUpdate(protocol.UpdateEvent{
 				ID:   eventID,
 				Data: data,
 			})
@@ -411,13 +805,13 @@ func (s *SystemService) SetWindowMode(mode string) error {
 	if err := s.configService.UpdateConfig(config); err != nil {
 		return err
 	}
-	s.app.Event.Emit("mode:change", map[string]string{"windowMode": mode})
+	s.emit("mode:change", map[string]string{"windowMode": mode})
 	return nil
 }
 
 // SetEditMode emits an edit mode toggle event to the frontend.
 func (s *SystemService) SetEditMode(enabled bool) {
-	s.app.Event.Emit("mode:change", map[string]interface{}{
+	s.emit("mode:change", map[string]interface{}{
 		"editMode": enabled,
 	})
 }
@@ -432,7 +826,7 @@ func (s *SystemService) UpdateOpacity(opacity float64) error {
 	if err := s.configService.UpdateConfig(config); err != nil {
 		return err
 	}
-	s.app.Event.Emit("config:update", map[string]interface{}{
+	s.emit("config:update", map[string]interface{}{
 		"opacity": opacity,
 	})
 	return nil
@@ -551,6 +945,77 @@ func (s *SystemService) GetModules() ([]ModuleInfo, error) {
 	return infos, nil
 }
 
+// TriggerAction dispatches a ConfigButton action to a widget. moduleID accepts
+// the short ID or the full render ID. Native modules must implement
+// modules.ActionHandler and are called without s.mu held; sidecars receive the
+// action on their next push, or immediately when connected over WebSocket.
+// Pulled and ingested sources never push, so they reject actions. The action
+// must be declared by a button in the widget's schema.
+func (s *SystemService) TriggerAction(moduleID string, action string) error {
+	s.mu.Lock()
+	src := s.findSourceLocked(moduleID)
+	if src == nil {
+		s.mu.Unlock()
+		return fmt.Errorf("widget %q not found", moduleID)
+	}
+	if !hasButtonAction(src.GetConfigSchema(), action) {
+		s.mu.Unlock()
+		return fmt.Errorf("widget %q has no action %q", moduleID, action)
+	}
+	if sc, ok := src.(*SidecarSource); ok {
+		defer s.mu.Unlock()
+		if sc.puller != nil || (sc.ingested && sc.conn == nil) {
+			return fmt.Errorf("widget %q cannot receive actions", moduleID)
+		}
+		sc.queueAction(action)
+		slog.Info("Queued sidecar action", "id", moduleID, "action", action)
+		return nil
+	}
+	s.mu.Unlock()
+
+	// Dispatch outside the lock so handlers may call back into the service.
+	h, ok := src.(modules.ActionHandler)
+	if !ok {
+		return fmt.Errorf("widget %q does not handle actions", moduleID)
+	}
+	slog.Info("Dispatching module action", "id", moduleID, "action", action)
+	return h.HandleAction(action)
+}
+
+// takeSidecarActions drains the actions queued for a sidecar.
+func (s *SystemService) takeSidecarActions(id string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sc, ok := s.sources[id].(*SidecarSource)
+	if !ok {
+		return nil
+	}
+	return sc.takeActions()
+}
+
+// findSourceLocked looks up a source by short ID, then by render ID.
+// Caller must hold s.mu.
+func (s *SystemService) findSourceLocked(id string) WidgetSource {
+	if src, ok := s.sources[id]; ok {
+		return src
+	}
+	for _, src := range s.sources {
+		if src.GetRenderConfig().ID == id {
+			return src
+		}
+	}
+	return nil
+}
+
+func hasButtonAction(schema []protocol.ConfigSchema, action string) bool {
+	for _, field := range schema {
+		if field.Type == protocol.ConfigButton && field.Action == action {
+			return true
+		}
+	}
+	return false
+}
+
 // GetModuleConfigSchema returns the config schema for a specific module.
 // Accepts either the short module ID ("disk") or the full render ID ("glancehud.core.disk").
 func (s *SystemService) GetModuleConfigSchema(moduleID string) ([]protocol.ConfigSchema, error) {
@@ -593,9 +1058,14 @@ func (s *SystemService) RemoveSidecar(id string) error {
 		s.mu.Unlock()
 		return fmt.Errorf("cannot remove native module %q", id)
 	}
+	if sc, ok := src.(*SidecarSource); ok && sc.puller != nil {
+		s.mu.Unlock()
+		return fmt.Errorf("widget %q is pulled from config; remove it from pulls instead", id)
+	}
 
 	// Remove from runtime state
 	delete(s.sources, id)
+	s.history.Remove(id)
 
 	// Remove from cache (sidecar cache key = config ID, same as source key)
 	renderID := src.GetRenderConfig().ID
@@ -622,13 +1092,159 @@ func (s *SystemService) RemoveSidecar(id string) error {
 	slog.Info("Removed sidecar from sources and config", "id", id)
 
 	// Notify frontend
-	if s.app != nil {
-		s.app.Event.Emit("config:reload", nil)
-	}
+	s.emit("config:reload", nil)
 
 	return nil
 }
 
+// GetHistory returns recorded values for a widget between from and to (Unix
+// milliseconds; 0 means unbounded), averaged into step-millisecond buckets when
+// step > 0. id accepts the short ID or the full render ID.
+func (s *SystemService) GetHistory(id string, from int64, to int64, step int64) (protocol.HistoryResponse, error) {
+	s.mu.RLock()
+	src := s.findSourceLocked(id)
+	s.mu.RUnlock()
+	if src == nil {
+		return protocol.HistoryResponse{}, fmt.Errorf("widget %q not found", id)
+	}
+
+	renderID := src.GetRenderConfig().ID
+	var fromT, toT time.Time
+	if from > 0 {
+		fromT = time.UnixMilli(from)
+	}
+	if to > 0 {
+		toT = time.UnixMilli(to)
+	}
+	return protocol.HistoryResponse{
+		ID:     renderID,
+		Points: s.history.Query(renderID, fromT, toT, time.Duration(step)*time.Millisecond),
+	}, nil
+}
+
+// GetAlerts returns the current state of every configured alert rule.
+func (s *SystemService) GetAlerts() protocol.AlertsResponse {
+	resp := protocol.AlertsResponse{Alerts: s.alerts.Statuses()}
+	if until := s.notifier.SnoozedUntil(); !until.IsZero() {
+		resp.SnoozedUntil = until.UnixMilli()
+	}
+	return resp
+}
+
+// SnoozeNotifications suppresses alert notifications for the given number of
+// seconds; 0 resumes them. Alerts keep being evaluated and emitted to the UI.
+func (s *SystemService) SnoozeNotifications(seconds int) {
+	if seconds <= 0 {
+		s.notifier.Snooze(time.Time{})
+		slog.Info("Alert notifications resumed")
+		return
+	}
+	until := time.Now().Add(time.Duration(seconds) * time.Second)
+	s.notifier.Snooze(until)
+	slog.Info("Alert notifications snoozed", "until", until)
+}
+
+// applyAlertRulesLocked compiles the configured alert rules, resolving short
+// widget IDs ("cpu") to render IDs so they match update event IDs. Rules for
+// sidecars that have not registered yet keep their ID as-is, since sidecar IDs
+// are already render IDs. Caller must hold s.mu.
+func (s *SystemService) applyAlertRulesLocked(rules []modules.AlertRule) {
+	resolved := make([]modules.AlertRule, len(rules))
+	for i, r := range rules {
+		if src := s.findSourceLocked(r.WidgetID); src != nil {
+			r.WidgetID = src.GetRenderConfig().ID
+		}
+		resolved[i] = r
+	}
+	for _, err := range s.alerts.SetRules(resolved) {
+		slog.Warn("Skipping invalid alert rule", "error", err)
+	}
+}
+
+// evaluateAlerts runs alert rules for one widget, emits "alert:fire" /
+// "alert:resolve" for each state transition and notifies the rule's
+// channels. Offline and error payloads are ignored so a stale value neither
+// fires nor resolves an alert.
+func (s *SystemService) evaluateAlerts(id string, data *protocol.DataPayload) {
+	if data == nil || isStale(data) {
+		return
+	}
+	now := time.Now()
+	fired, resolved := s.alerts.Evaluate(id, data, now)
+	for _, st := range fired {
+		slog.Warn("Alert firing", "name", st.Name, "widget", st.WidgetID, "path", st.Path, "value", st.Value)
+		s.emit("alert:fire", st)
+		s.notifier.Dispatch(st.Notify, notify.NewEvent(notify.KindFire, st, now))
+	}
+	for _, st := range resolved {
+		slog.Info("Alert resolved", "name", st.Name, "widget", st.WidgetID)
+		s.emit("alert:resolve", st)
+		s.notifier.Dispatch(st.Notify, notify.NewEvent(notify.KindResolve, st, now))
+	}
+}
+
+// ServiceShutdown is called by Wails on exit (and by headless main on signal)
+// to flush history to disk.
+func (s *SystemService) ServiceShutdown() error {
+	return s.flushHistory()
+}
+
+func (s *SystemService) runHistoryFlusher() {
+	ticker := time.NewTicker(HistoryFlushInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := s.flushHistory(); err != nil {
+			slog.Error("Failed to persist history", "path", s.historyPath, "error", err)
+		}
+	}
+}
+
+// flushHistory saves history when persistence is enabled in config.
+func (s *SystemService) flushHistory() error {
+	if !s.configService.GetConfig().PersistHistory {
+		return nil
+	}
+	return s.history.Save(s.historyPath)
+}
+
+// historyValue extracts the numeric Value from a payload. Offline and error
+// payloads are skipped since they only repeat the last known value.
+func historyValue(data *protocol.DataPayload) (float64, bool) {
+	if data == nil || isStale(data) {
+		return 0, false
+	}
+	return numericValue(data.Value)
+}
+
+// isStale reports whether a payload repeats old data: an offline sidecar, or
+// a source whose last run failed (props.error).
+func isStale(data *protocol.DataPayload) bool {
+	if offline, _ := data.Props["isOffline"].(bool); offline {
+		return true
+	}
+	_, failed := data.Props["error"].(string)
+	return failed
+}
+
+// numericValue converts a DataPayload.Value to float64 when it holds a number.
+// Sidecar values arrive as float64 from JSON; native modules may use ints.
+func numericValue(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
 // resolveConfigDir returns the OS-appropriate user config directory for GlanceHUD.
 // Falls back to "." (current directory) if the OS config dir cannot be determined.
 //
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"glancehud/internal/alert"
+	"glancehud/internal/history"
+	"glancehud/internal/modules"
+	"glancehud/internal/notify"
+	"glancehud/internal/protocol"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingSink captures emitted events for assertions.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []recordedEvent
+}
+
+type recordedEvent struct {
+	name string
+	data any
+}
+
+func (r *recordingSink) Emit(name string, data any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, recordedEvent{name: name, data: data})
+}
+
+func (r *recordingSink) named(name string) []recordedEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []recordedEvent
+	for _, e := range r.events {
+		if e.name == name {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// newTestService builds a SystemService without native modules or a TTL
+// goroutine, backed by a config file in a temp dir.
+func newTestService(t *testing.T) *SystemService {
+	t.Helper()
+	cs, err := modules.NewConfigService(t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("NewConfigService: %v", err)
+	}
+	return &SystemService{
+		configService: cs,
+		sources:       make(map[string]WidgetSource),
+		stopChans:     make(map[string]chan struct{}),
+		cache:         make(map[string]*protocol.DataPayload),
+		history:       history.NewStore(100),
+		alerts:        alert.NewEngine(),
+		notifier:      notify.NewDispatcher(),
+		influxIDs:     make(map[string]struct{}),
+	}
+}
+
+// --- Headless (nil sink) ---
+
+func TestSystemService_NilSink_DoesNotPanic(t *testing.T) {
+	s := newTestService(t)
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+
+	s.SetEditMode(true)
+	if err := s.SetWindowMode("locked"); err != nil {
+		t.Fatalf("SetWindowMode: %v", err)
+	}
+	if err := s.UpdateOpacity(0.5); err != nil {
+		t.Fatalf("UpdateOpacity: %v", err)
+	}
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 1.0})
+}
+
+// --- Event routing ---
+
+func TestSystemService_UpdateSidecarData_EmitsStatsUpdate(t *testing.T) {
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 42.0})
+
+	got := sink.named("stats:update")
+	if len(got) != 1 {
+		t.Fatalf("expected 1 stats:update, got %d", len(got))
+	}
+	ev, ok := got[0].data.(protocol.UpdateEvent)
+	if !ok {
+		t.Fatalf("expected UpdateEvent, got %T", got[0].data)
+	}
+	if ev.ID != "custom.x" || ev.Data.Value != 42.0 {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestSystemService_CheckSidecarTTL_EmitsOffline(t *testing.T) {
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+	sc := newTestSidecar("custom.x")
+	sc.lastSeen = time.Now().Add(-2 * SidecarTTL)
+	s.sources["custom.x"] = sc
+
+	s.checkSidecarTTL()
+
+	got := sink.named("stats:update")
+	if len(got) != 1 {
+		t.Fatalf("expected 1 stats:update, got %d", len(got))
+	}
+	ev := got[0].data.(protocol.UpdateEvent)
+	if ev.Data.Props["isOffline"] != true {
+		t.Errorf("expected isOffline prop, got %v", ev.Data.Props)
+	}
+	if !sc.isOffline {
+		t.Error("source should be marked offline")
+	}
+
+	// A second pass must not re-emit for an already-offline source
+	s.checkSidecarTTL()
+	if n := len(sink.named("stats:update")); n != 1 {
+		t.Errorf("expected no additional events, got %d total", n)
+	}
+}
+
+func TestSystemService_SetWindowMode_EmitsModeChange(t *testing.T) {
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+
+	if err := s.SetWindowMode("locked"); err != nil {
+		t.Fatalf("SetWindowMode: %v", err)
+	}
+	if len(sink.named("mode:change")) != 1 {
+		t.Error("expected a mode:change event")
+	}
+	if s.GetConfig().WindowMode != "locked" {
+		t.Errorf("WindowMode not persisted: %q", s.GetConfig().WindowMode)
+	}
+}
+
+// steadyModule reports the same value on every fast tick.
+type steadyModule struct{}
+
+func (steadyModule) ID() string { return "steady" }
+func (steadyModule) GetRenderConfig() protocol.RenderConfig {
+	return protocol.RenderConfig{ID: "glancehud.test.steady", Type: protocol.TypeSpark}
+}
+func (steadyModule) GetConfigSchema() []protocol.ConfigSchema { return nil }
+func (steadyModule) Update() (*protocol.DataPayload, error) {
+	return &protocol.DataPayload{Value: 42.0}, nil
+}
+func (steadyModule) ApplyConfig(_ map[string]interface{}) {}
+func (steadyModule) Interval() time.Duration              { return 5 * time.Millisecond }
+
+func TestRunMonitor_RecordsHistoryWhenUnchanged(t *testing.T) {
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		s.runMonitor(steadyModule{}, "steady", stop)
+		close(done)
+	}()
+	time.Sleep(60 * time.Millisecond)
+	close(stop)
+	<-done
+
+	if n := len(sink.named("stats:update")); n != 1 {
+		t.Errorf("unchanged data should emit once, got %d", n)
+	}
+	if n := len(s.history.Query("steady", time.Time{}, time.Time{}, 0)); n < 3 {
+		t.Errorf("expected a history sample per tick, got %d", n)
+	}
+}
+
+// --- Actions ---
+
+// actionModule is a native module stub that records dispatched actions.
+type actionModule struct {
+	handled []string
+}
+
+func (m *actionModule) ID() string { return "act" }
+func (m *actionModule) GetRenderConfig() protocol.RenderConfig {
+	return protocol.RenderConfig{ID: "glancehud.test.act", Type: protocol.TypeKeyValue}
+}
+func (m *actionModule) GetConfigSchema() []protocol.ConfigSchema {
+	return []protocol.ConfigSchema{
+		{Label: "Reset Peak", Type: protocol.ConfigButton, Action: "reset_peak"},
+	}
+}
+func (m *actionModule) Update() (*protocol.DataPayload, error) { return nil, nil }
+func (m *actionModule) ApplyConfig(_ map[string]interface{})   {}
+func (m *actionModule) Interval() time.Duration                { return time.Second }
+func (m *actionModule) HandleAction(action string) error {
+	m.handled = append(m.handled, action)
+	return nil
+}
+
+func TestTriggerAction_NativeModule(t *testing.T) {
+	s := newTestService(t)
+	mod := &actionModule{}
+	s.sources["act"] = mod
+
+	// Both short ID and render ID resolve to the module
+	if err := s.TriggerAction("act", "reset_peak"); err != nil {
+		t.Fatalf("TriggerAction(short id): %v", err)
+	}
+	if err := s.TriggerAction("glancehud.test.act", "reset_peak"); err != nil {
+		t.Fatalf("TriggerAction(render id): %v", err)
+	}
+	if len(mod.handled) != 2 {
+		t.Errorf("expected 2 handled actions, got %v", mod.handled)
+	}
+}
+
+func TestTriggerAction_UndeclaredActionRejected(t *testing.T) {
+	s := newTestService(t)
+	s.sources["act"] = &actionModule{}
+
+	if err := s.TriggerAction("act", "format_disk"); err == nil {
+		t.Error("expected error for action not declared in schema")
+	}
+	if err := s.TriggerAction("missing", "reset_peak"); err == nil {
+		t.Error("expected error for unknown widget")
+	}
+}
+
+func TestTriggerAction_SidecarQueuedUntilNextPush(t *testing.T) {
+	s := newTestService(t)
+	sc := newTestSidecar("custom.train")
+	sc.schema = []protocol.ConfigSchema{
+		{Label: "Restart", Type: protocol.ConfigButton, Action: "restart"},
+	}
+	s.sources["custom.train"] = sc
+
+	if err := s.TriggerAction("custom.train", "restart"); err != nil {
+		t.Fatalf("TriggerAction: %v", err)
+	}
+
+	acts := s.takeSidecarActions("custom.train")
+	if len(acts) != 1 || acts[0] != "restart" {
+		t.Errorf("expected [restart], got %v", acts)
+	}
+	if again := s.takeSidecarActions("custom.train"); len(again) != 0 {
+		t.Errorf("actions should be drained, got %v", again)
+	}
+}
+
+// reentrantModule calls back into the service from HandleAction.
+type reentrantModule struct {
+	actionModule
+	s *SystemService
+}
+
+func (m *reentrantModule) HandleAction(action string) error {
+	m.s.emit("test:action", action)
+	return m.actionModule.HandleAction(action)
+}
+
+func TestTriggerAction_HandlerMayCallService(t *testing.T) {
+	s := newTestService(t)
+	mod := &reentrantModule{s: s}
+	s.sources["act"] = mod
+
+	done := make(chan error, 1)
+	go func() { done <- s.TriggerAction("act", "reset_peak") }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("TriggerAction: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("TriggerAction deadlocked")
+	}
+	if len(mod.handled) != 1 {
+		t.Errorf("expected 1 handled action, got %v", mod.handled)
+	}
+}
+
+func TestTriggerAction_PassiveSidecarRejected(t *testing.T) {
+	s := newTestService(t)
+	sc := newTestSidecar("statsd.jobs")
+	sc.schema = []protocol.ConfigSchema{
+		{Label: "Restart", Type: protocol.ConfigButton, Action: "restart"},
+	}
+	sc.ingested = true
+	s.sources["statsd.jobs"] = sc
+
+	if err := s.TriggerAction("statsd.jobs", "restart"); err == nil {
+		t.Error("expected error for ingested source")
+	}
+
+	sc.ingested = false
+	sc.puller = &PullSource{}
+	if err := s.TriggerAction("statsd.jobs", "restart"); err == nil {
+		t.Error("expected error for pulled source")
+	}
+	if acts := s.takeSidecarActions("statsd.jobs"); len(acts) != 0 {
+		t.Errorf("rejected actions should not be queued, got %v", acts)
+	}
+}
+
+// --- Alerts ---
+
+func TestEvaluateAlerts_EmitsFireAndResolve(t *testing.T) {
+	s := newTestService(t)
+	sink := &recordingSink{}
+	s.sink = sink
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+	s.applyAlertRulesLocked([]modules.AlertRule{
+		{Name: "x-high", WidgetID: "custom.x", Path: "value", Op: ">", Threshold: 5},
+	})
+
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 10.0})
+	fired := sink.named("alert:fire")
+	if len(fired) != 1 {
+		t.Fatalf("want 1 alert:fire, got %d", len(fired))
+	}
+	if st := fired[0].data.(protocol.AlertStatus); st.Name != "x-high" {
+		t.Errorf("want x-high, got %+v", st)
+	}
+
+	// Offline payloads must not resolve the alert
+	s.evaluateAlerts("custom.x", &protocol.DataPayload{Value: 0.0, Props: map[string]any{"isOffline": true}})
+	if n := len(sink.named("alert:resolve")); n != 0 {
+		t.Errorf("want no resolve for offline payload, got %d", n)
+	}
+
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 1.0})
+	if n := len(sink.named("alert:resolve")); n != 1 {
+		t.Errorf("want 1 alert:resolve, got %d", n)
+	}
+	if got := s.GetAlerts().Alerts[0].State; got != protocol.AlertOK {
+		t.Errorf("want ok, got %s", got)
+	}
+}
+
+func TestApplyAlertRules_ResolvesShortID(t *testing.T) {
+	s := newTestService(t)
+	s.sources["act"] = &actionModule{}
+	s.applyAlertRulesLocked([]modules.AlertRule{
+		{Name: "a", WidgetID: "act", Path: "value", Op: ">", Threshold: 1},
+	})
+	if got := s.GetAlerts().Alerts[0].WidgetID; got != "glancehud.test.act" {
+		t.Errorf("want glancehud.test.act, got %q", got)
+	}
+}
+
+func TestEvaluateAlerts_NotifiesChannels(t *testing.T) {
+	got := make(chan string, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		got <- string(b)
+	}))
+	defer hook.Close()
+
+	s := newTestService(t)
+	s.sources["custom.x"] = newTestSidecar("custom.x")
+	s.applyAlertRulesLocked([]modules.AlertRule{
+		{Name: "x-high", WidgetID: "custom.x", Path: "value", Op: ">", Threshold: 5, Notify: []string{"hook"}},
+	})
+	s.notifier.SetChannels([]modules.NotifierConfig{
+		{Name: "hook", Type: "webhook", URL: hook.URL, Body: `{{.Kind}}:{{.Name}}`},
+	})
+
+	s.UpdateSidecarData("custom.x", &protocol.DataPayload{Value: 10.0})
+	select {
+	case body := <-got:
+		if body != "fire:x-high" {
+			t.Errorf("want fire:x-high, got %q", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestSnoozeNotifications(t *testing.T) {
+	s := newTestService(t)
+	s.SnoozeNotifications(3600)
+	if s.GetAlerts().SnoozedUntil == 0 {
+		t.Error("want snoozedUntil set")
+	}
+	s.SnoozeNotifications(0)
+	if got := s.GetAlerts().SnoozedUntil; got != 0 {
+		t.Errorf("want 0 after resume, got %d", got)
+	}
+}
+
+// --- Host paths ---
+
+func TestSystemService_HostPathPrecedence(t *testing.T) {
+	s := newTestService(t)
+	cfg := s.GetConfig()
+	cfg.Host = modules.HostPaths{Proc: "/cfg/proc", Sys: "/cfg/sys", Etc: "/cfg/etc"}
+	if err := s.configService.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOST_PROC", "")
+	t.Setenv("HOST_SYS", "/env/sys")
+	t.Setenv("HOST_ETC", "/env/etc")
+	t.Setenv("HOST_ROOT", "")
+	t.Cleanup(func() { modules.SetHostPaths(modules.HostPaths{}) })
+
+	s.SetHostOverrides(modules.HostPaths{Etc: "/cli/etc"})
+	s.StartMonitoring()
+
+	want := modules.HostPaths{Proc: "/cfg/proc", Sys: "/env/sys", Etc: "/cli/etc"}
+	if got := modules.CurrentHostPaths(); got != want {
+		t.Errorf("want %+v, got %+v", want, got)
+	}
+}
+
+// --- Exec widgets ---
+
+func TestSystemService_SyncExecWidgets(t *testing.T) {
+	s := newTestService(t)
+	cfg := s.GetConfig()
+	cfg.Widgets = append(cfg.Widgets, modules.WidgetConfig{ID: "exec.gone", Enabled: true})
+	cfg.Exec = []modules.ExecConfig{
+		{Name: "queue", Command: []string{"true"}, Interval: "1h"},
+		{Name: "broken", Command: []string{"true"}, Parser: "xml"},
+	}
+	if err := s.configService.UpdateConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	s.StartMonitoring()
+	queue, ok := s.sources["exec.queue"].(*modules.ExecModule)
+	if !ok {
+		t.Fatal("want exec.queue source")
+	}
+	if _, ok := s.sources["exec.broken"]; ok {
+		t.Error("invalid exec entry should not get a source")
+	}
+	ids := map[string]bool{}
+	for _, w := range s.GetConfig().Widgets {
+		ids[w.ID] = w.Enabled
+	}
+	if !ids["exec.queue"] || !ids["exec.broken"] {
+		t.Errorf("want enabled widgets for listed entries, got %v", ids)
+	}
+	if _, ok := ids["exec.gone"]; ok {
+		t.Error("widget for removed exec entry should be pruned")
+	}
+
+	// Unchanged entries keep their module across restarts
+	s.StartMonitoring()
+	if s.sources["exec.queue"] != queue {
+		t.Error("unchanged exec module was rebuilt")
+	}
+
+	cfg = s.GetConfig()
+	cfg.Exec = nil
+	if err := s.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.sources["exec.queue"]; ok {
+		t.Error("removed exec entry should drop its source")
+	}
+	for _, w := range s.GetConfig().Widgets {
+		if w.ID == "exec.queue" {
+			t.Error("removed exec entry should drop its widget")
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"glancehud/internal/protocol"
+	"sync"
+)
+
+// updateSubscriberBuffer is the per-subscriber channel capacity. A subscriber
+// that falls further behind than this starts losing updates instead of
+// blocking the publisher.
+const updateSubscriberBuffer = 64
+
+// updateHub fans out widget UpdateEvents to any number of external consumers
+// (SSE clients, WebSocket sidecars, ...). The zero value is ready to use.
+//
+// publish never blocks: monitor goroutines must keep ticking even when a
+// consumer stalls, so a full subscriber channel simply drops the event.
+type updateHub struct {
+	mu   sync.Mutex
+	subs map[chan protocol.UpdateEvent]struct{}
+}
+
+// subscribe registers a new consumer. The returned cancel func unregisters it
+// and closes the channel; it is safe to call more than once.
+func (h *updateHub) subscribe() (<-chan protocol.UpdateEvent, func()) {
+	ch := make(chan protocol.UpdateEvent, updateSubscriberBuffer)
+
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = make(map[chan protocol.UpdateEvent]struct{})
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, ch)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// publish delivers ev to every subscriber that has room for it.
+func (h *updateHub) publish(ev protocol.UpdateEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- ev:
+		default:
+			// Slow consumer: drop rather than stall the monitor goroutine.
+		}
+	}
+}
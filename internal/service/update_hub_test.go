@@ -0,0 +1,61 @@
+package service
+
+import (
+	"glancehud/internal/protocol"
+	"testing"
+	"time"
+)
+
+func TestUpdateHub_FanOut(t *testing.T) {
+	var h updateHub
+	a, cancelA := h.subscribe()
+	defer cancelA()
+	b, cancelB := h.subscribe()
+	defer cancelB()
+
+	h.publish(protocol.UpdateEvent{ID: "x"})
+
+	for name, ch := range map[string]<-chan protocol.UpdateEvent{"a": a, "b": b} {
+		select {
+		case ev := <-ch:
+			if ev.ID != "x" {
+				t.Errorf("%s: want ID 'x', got %q", name, ev.ID)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("%s: did not receive event", name)
+		}
+	}
+}
+
+func TestUpdateHub_SlowConsumerDoesNotBlock(t *testing.T) {
+	var h updateHub
+	_, cancel := h.subscribe() // never drained
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < updateSubscriberBuffer*4; i++ {
+			h.publish(protocol.UpdateEvent{ID: "x"})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publish blocked on a full subscriber")
+	}
+}
+
+func TestUpdateHub_CancelClosesChannel(t *testing.T) {
+	var h updateHub
+	ch, cancel := h.subscribe()
+	cancel()
+	cancel() // idempotent
+
+	if _, ok := <-ch; ok {
+		t.Error("expected channel to be closed after cancel")
+	}
+	// Publishing after cancel must not panic on the closed channel
+	h.publish(protocol.UpdateEvent{ID: "x"})
+}
@@ -2,9 +2,14 @@ package service
 
 import (
 	"glancehud/internal/protocol"
+	"reflect"
 	"time"
 )
 
+// maxPendingActions bounds the actions queued for a sidecar that has not
+// pushed recently; the oldest are dropped first.
+const maxPendingActions = 16
+
 // WidgetSource is the unified interface for all widget data sources.
 // modules.Module (native) and *SidecarSource (sidecar) both satisfy this interface.
 type WidgetSource interface {
@@ -17,13 +22,17 @@ type WidgetSource interface {
 // SidecarSource replaces the former SidecarModule struct.
 // It implements WidgetSource for HTTP-push sidecar widgets.
 type SidecarSource struct {
-	id           string
-	config       protocol.RenderConfig
-	schema       []protocol.ConfigSchema
-	currentData  *protocol.DataPayload
-	lastSeen     time.Time
-	isOffline    bool
-	currentProps map[string]interface{}
+	id             string
+	config         protocol.RenderConfig
+	schema         []protocol.ConfigSchema
+	currentData    *protocol.DataPayload
+	lastSeen       time.Time
+	isOffline      bool
+	currentProps   map[string]interface{}
+	conn           *sidecarConn // non-nil while a WebSocket sidecar is attached
+	puller         *PullSource  // non-nil for URLs GlanceHUD polls itself
+	ingested       bool         // fed by StatsD or line protocol, never pushes
+	pendingActions []string     // ConfigButton actions awaiting delivery
 }
 
 func (s *SidecarSource) ID() string {
@@ -39,12 +48,46 @@ func (s *SidecarSource) GetConfigSchema() []protocol.ConfigSchema {
 }
 
 // ApplyConfig stores the merged props so they can be returned to the sidecar via HTTP response.
+// If a WebSocket sidecar is attached and the props changed, they are pushed immediately.
 func (s *SidecarSource) ApplyConfig(props map[string]interface{}) {
 	copied := make(map[string]interface{}, len(props))
 	for k, v := range props {
 		copied[k] = v
 	}
+	changed := !reflect.DeepEqual(s.currentProps, copied)
 	s.currentProps = copied
+
+	if changed && s.conn != nil {
+		s.conn.send(protocol.SidecarResponse{
+			Status:   "ok",
+			ModuleID: s.id,
+			Props:    copied,
+		})
+	}
+}
+
+// queueAction delivers a ConfigButton action to the sidecar: immediately over an
+// attached WebSocket, otherwise in the response to its next HTTP push.
+func (s *SidecarSource) queueAction(action string) {
+	if s.conn != nil {
+		s.conn.send(protocol.SidecarResponse{
+			Status:   "ok",
+			ModuleID: s.id,
+			Actions:  []string{action},
+		})
+		return
+	}
+	if len(s.pendingActions) >= maxPendingActions {
+		s.pendingActions = s.pendingActions[1:]
+	}
+	s.pendingActions = append(s.pendingActions, action)
+}
+
+// takeActions returns and clears the queued actions.
+func (s *SidecarSource) takeActions() []string {
+	acts := s.pendingActions
+	s.pendingActions = nil
+	return acts
 }
 
 // updateTemplate refreshes the render config and schema when the sidecar sends a new template.
@@ -200,3 +200,39 @@ func TestSidecarSource_NotOfflineWithinTTL(t *testing.T) {
 func TestSidecarSource_ImplementsWidgetSource(t *testing.T) {
 	var _ WidgetSource = (*SidecarSource)(nil)
 }
+
+// --- queueAction ---
+
+func TestSidecarSource_QueueAction_Bounded(t *testing.T) {
+	s := newTestSidecar("custom.x")
+	for i := 0; i < maxPendingActions+3; i++ {
+		s.queueAction("a")
+	}
+	s.queueAction("last")
+
+	acts := s.takeActions()
+	if len(acts) != maxPendingActions {
+		t.Fatalf("expected %d queued actions, got %d", maxPendingActions, len(acts))
+	}
+	if acts[len(acts)-1] != "last" {
+		t.Errorf("newest action should be kept, got %q", acts[len(acts)-1])
+	}
+}
+
+func TestSidecarSource_QueueAction_PushesOverSocket(t *testing.T) {
+	s := newTestSidecar("custom.x")
+	s.conn = newSidecarConn()
+	s.queueAction("restart")
+
+	if len(s.takeActions()) != 0 {
+		t.Error("action should not be queued when a socket is attached")
+	}
+	select {
+	case resp := <-s.conn.out:
+		if len(resp.Actions) != 1 || resp.Actions[0] != "restart" || resp.ModuleID != "custom.x" {
+			t.Errorf("unexpected frame: %+v", resp)
+		}
+	default:
+		t.Error("expected a frame on the socket")
+	}
+}
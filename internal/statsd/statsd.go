@@ -0,0 +1,234 @@
+// Package statsd parses the StatsD line format and aggregates metrics between
+// flushes: counters become per-second rates, gauges keep their last value and
+// timers are summarised as mean, min, max and 90th percentile.
+package statsd
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is a StatsD metric type.
+type Kind string
+
+const (
+	Counter Kind = "c"
+	Gauge   Kind = "g"
+	Timer   Kind = "ms" // histograms ("h") and distributions ("d") count as timers
+)
+
+// MaxMetrics caps how many distinct metric names an Aggregator tracks, so a
+// misbehaving client cannot create an unbounded number of widgets.
+const MaxMetrics = 500
+
+// MaxIdleFlushes is how many flushes in a row a name may go without samples
+// before the Aggregator forgets it and frees its MaxMetrics slot.
+const MaxIdleFlushes = 12
+
+// Metric is one parsed sample.
+type Metric struct {
+	Name  string
+	Kind  Kind
+	Value float64
+	Rate  float64 // sample rate from "|@0.1"; 1 when absent
+	Delta bool    // gauge written as "+n" / "-n": adjust instead of set
+}
+
+// ParsePacket parses every newline-separated line of a UDP packet. Bad lines
+// are skipped and reported together in the returned error.
+func ParsePacket(b []byte) ([]Metric, error) {
+	var out []Metric
+	var errs []error
+	for line := range strings.SplitSeq(string(b), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		m, err := ParseLine(line)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%q: %w", line, err))
+			continue
+		}
+		out = append(out, m)
+	}
+	return out, errors.Join(errs...)
+}
+
+// ParseLine parses `name:value|type[|@rate][|#tags]`. Tags are ignored.
+func ParseLine(line string) (Metric, error) {
+	name, rest, ok := strings.Cut(line, ":")
+	if !ok || name == "" {
+		return Metric{}, errors.New("missing name")
+	}
+	parts := strings.Split(rest, "|")
+	if len(parts) < 2 {
+		return Metric{}, errors.New("missing type")
+	}
+	m := Metric{Name: Sanitize(name), Rate: 1}
+	switch parts[1] {
+	case "c":
+		m.Kind = Counter
+	case "g":
+		m.Kind = Gauge
+	case "ms", "h", "d":
+		m.Kind = Timer
+	default:
+		return Metric{}, fmt.Errorf("unsupported type %q", parts[1])
+	}
+	raw := parts[0]
+	if m.Kind == Gauge && (strings.HasPrefix(raw, "+") || strings.HasPrefix(raw, "-")) {
+		m.Delta = true
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return Metric{}, fmt.Errorf("bad value %q", raw)
+	}
+	m.Value = v
+	for _, p := range parts[2:] {
+		if r, ok := strings.CutPrefix(p, "@"); ok {
+			if m.Rate, err = strconv.ParseFloat(r, 64); err != nil || m.Rate <= 0 || m.Rate > 1 {
+				return Metric{}, fmt.Errorf("bad sample rate %q", r)
+			}
+		}
+	}
+	return m, nil
+}
+
+// Sanitize keeps letters, digits, '.', '_' and '-' so names are safe to use
+// in widget IDs; anything else becomes '_'.
+func Sanitize(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// Stat is one metric's aggregate for a flush interval. Extra holds secondary
+// values: "count" for counters and timers, "min", "max" and "p90" for timers.
+// Fresh reports whether the metric received samples during the interval.
+type Stat struct {
+	Name  string
+	Kind  Kind
+	Value float64
+	Extra map[string]float64
+	Fresh bool
+}
+
+// Aggregator accumulates metrics between flushes. It is safe for concurrent
+// use by a reader goroutine and a flusher.
+type Aggregator struct {
+	mu       sync.Mutex
+	kinds    map[string]Kind
+	idle     map[string]int // flushes since the name's last sample
+	counters map[string]float64
+	gauges   map[string]float64
+	timers   map[string][]float64
+}
+
+// NewAggregator returns an empty Aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		kinds:    make(map[string]Kind),
+		idle:     make(map[string]int),
+		counters: make(map[string]float64),
+		gauges:   make(map[string]float64),
+		timers:   make(map[string][]float64),
+	}
+}
+
+// Add records m. It reports false when m was dropped: its name is already
+// used by another kind, or MaxMetrics names are tracked.
+func (a *Aggregator) Add(m Metric) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if k, ok := a.kinds[m.Name]; ok && k != m.Kind {
+		return false
+	} else if !ok {
+		if len(a.kinds) >= MaxMetrics {
+			return false
+		}
+		a.kinds[m.Name] = m.Kind
+	}
+	a.idle[m.Name] = 0
+	switch m.Kind {
+	case Counter:
+		a.counters[m.Name] += m.Value / m.Rate
+	case Gauge:
+		if m.Delta {
+			a.gauges[m.Name] += m.Value
+		} else {
+			a.gauges[m.Name] = m.Value
+		}
+	case Timer:
+		a.timers[m.Name] = append(a.timers[m.Name], m.Value)
+	}
+	return true
+}
+
+// Flush returns the aggregates for the interval that just ended, sorted by
+// name, and starts a new one. As in statsd, counters seen before report 0
+// when idle and gauges repeat their last value; timers without samples are
+// omitted. Names idle for MaxIdleFlushes are forgotten.
+func (a *Aggregator) Flush(interval time.Duration) []Stat {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	secs := interval.Seconds()
+	out := make([]Stat, 0, len(a.kinds))
+	for name, kind := range a.kinds {
+		idle := a.idle[name]
+		if idle >= MaxIdleFlushes {
+			delete(a.kinds, name)
+			delete(a.idle, name)
+			delete(a.counters, name)
+			delete(a.gauges, name)
+			delete(a.timers, name)
+			continue
+		}
+		a.idle[name] = idle + 1
+		st := Stat{Name: name, Kind: kind, Fresh: idle == 0}
+		switch kind {
+		case Counter:
+			n := a.counters[name]
+			st.Value, st.Extra = n/secs, map[string]float64{"count": n}
+			a.counters[name] = 0
+		case Gauge:
+			st.Value = a.gauges[name]
+		case Timer:
+			samples := a.timers[name]
+			if len(samples) == 0 {
+				continue
+			}
+			st.Value, st.Extra = summarize(samples)
+			delete(a.timers, name)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// summarize returns the mean of timer samples, plus count, min, max and p90.
+func summarize(samples []float64) (float64, map[string]float64) {
+	sort.Float64s(samples)
+	sum := 0.0
+	for _, v := range samples {
+		sum += v
+	}
+	n := len(samples)
+	p90 := samples[int(math.Ceil(0.9*float64(n)))-1]
+	return sum / float64(n), map[string]float64{
+		"count": float64(n),
+		"min":   samples[0],
+		"max":   samples[n-1],
+		"p90":   p90,
+	}
+}
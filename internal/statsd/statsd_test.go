@@ -0,0 +1,99 @@
+package statsd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want Metric
+	}{
+		{"api.requests:1|c", Metric{Name: "api.requests", Kind: Counter, Value: 1, Rate: 1}},
+		{"api.requests:2|c|@0.5", Metric{Name: "api.requests", Kind: Counter, Value: 2, Rate: 0.5}},
+		{"queue.depth:42|g", Metric{Name: "queue.depth", Kind: Gauge, Value: 42, Rate: 1}},
+		{"queue.depth:-3|g", Metric{Name: "queue.depth", Kind: Gauge, Value: -3, Rate: 1, Delta: true}},
+		{"db.query:12.5|ms|#env:prod", Metric{Name: "db.query", Kind: Timer, Value: 12.5, Rate: 1}},
+		{"db.size:7|h", Metric{Name: "db.size", Kind: Timer, Value: 7, Rate: 1}},
+		{"my app/hits:1|c", Metric{Name: "my_app_hits", Kind: Counter, Value: 1, Rate: 1}},
+	}
+	for _, tt := range tests {
+		got, err := ParseLine(tt.line)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %+v, %v", tt.line, got, err)
+		}
+	}
+
+	for _, bad := range []string{"novalue", ":1|c", "x:1", "x:1|s", "x:abc|c", "x:1|c|@2", "x:NaN|g"} {
+		if _, err := ParseLine(bad); err == nil {
+			t.Errorf("want error for %q", bad)
+		}
+	}
+
+	metrics, err := ParsePacket([]byte("a:1|c\n\nbad\nb:2|g\n"))
+	if len(metrics) != 2 || err == nil {
+		t.Errorf("packet: got %+v, %v", metrics, err)
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	a := NewAggregator()
+	for _, line := range []string{
+		"hits:3|c", "hits:1|c|@0.5", "temp:20|g", "temp:+5|g",
+		"lat:10|ms", "lat:20|ms", "lat:30|ms", "lat:100|ms",
+	} {
+		m, _ := ParseLine(line)
+		a.Add(m)
+	}
+	if m, _ := ParseLine("hits:5|g"); a.Add(m) {
+		t.Error("a name keeps its first kind")
+	}
+
+	stats := a.Flush(10 * time.Second)
+	if len(stats) != 3 {
+		t.Fatalf("want 3 stats, got %+v", stats)
+	}
+	hits, lat, temp := stats[0], stats[1], stats[2]
+	if hits.Name != "hits" || hits.Value != 0.5 || hits.Extra["count"] != 5 {
+		t.Errorf("counter: %+v", hits)
+	}
+	if lat.Value != 40 || lat.Extra["p90"] != 100 || lat.Extra["min"] != 10 || lat.Extra["count"] != 4 {
+		t.Errorf("timer: %+v", lat)
+	}
+	if temp.Value != 25 {
+		t.Errorf("gauge: %+v", temp)
+	}
+
+	// Idle interval: counters report 0, gauges repeat, timers drop out
+	stats = a.Flush(10 * time.Second)
+	if len(stats) != 2 || stats[0].Value != 0 || stats[1].Value != 25 {
+		t.Errorf("idle flush: %+v", stats)
+	}
+	if stats[0].Fresh || stats[1].Fresh {
+		t.Errorf("idle stats should not be fresh: %+v", stats)
+	}
+}
+
+func TestAggregator_ForgetsIdleNames(t *testing.T) {
+	a := NewAggregator()
+	for i := 0; i < MaxMetrics; i++ {
+		a.Add(Metric{Name: fmt.Sprintf("g%d", i), Kind: Gauge, Value: 1, Rate: 1})
+	}
+	if a.Add(Metric{Name: "late", Kind: Gauge, Value: 1, Rate: 1}) {
+		t.Fatal("want new name dropped at MaxMetrics")
+	}
+	if stats := a.Flush(time.Second); len(stats) != MaxMetrics || !stats[0].Fresh {
+		t.Fatalf("first flush: %d stats", len(stats))
+	}
+	for i := 0; i < MaxIdleFlushes; i++ {
+		a.Flush(time.Second)
+	}
+	if stats := a.Flush(time.Second); len(stats) != 0 {
+		t.Errorf("idle names should be forgotten, got %d", len(stats))
+	}
+	if !a.Add(Metric{Name: "late", Kind: Gauge, Value: 1, Rate: 1}) {
+		t.Error("forgotten names should free MaxMetrics slots")
+	}
+}
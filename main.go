@@ -1,13 +1,18 @@
+//go:build !headless
+
 package main
 
 import (
 	"embed"
+	"fmt"
 	"glancehud/internal/service"
 	"log"
 	"runtime"
+	"time"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 	"github.com/wailsapp/wails/v3/pkg/icons"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
 )
 
 //go:embed all:frontend/dist
@@ -16,7 +21,9 @@ var assets embed.FS
 func main() {
 	// custom service
 	systemService := service.NewSystemService()
+	systemService.SetHostOverrides(hostFlags())
 	apiService := service.NewAPIService(systemService)
+	notifier := notifications.New()
 
 	app := application.New(application.Options{
 		Name:        "GlanceHUD",
@@ -24,6 +31,7 @@ func main() {
 		Services: []application.Service{
 			application.NewService(systemService),
 			application.NewService(apiService),
+			application.NewService(notifier),
 		},
 		Assets: application.AssetOptions{
 			Handler: application.AssetFileServerFS(assets),
@@ -33,9 +41,10 @@ func main() {
 		},
 	})
 
-	// Inject app instance and start monitoring
-	systemService.Start(app)
-	apiService.Start(app)
+	// Route backend events to the Wails event bus, alert notifications to the
+	// OS, and start monitoring
+	systemService.Start(&wailsSink{app: app, notifier: notifier})
+	apiService.Start()
 
 	// Load config to check initial windowMode
 	config := systemService.GetConfig()
@@ -109,6 +118,25 @@ func main() {
 	}
 }
 
+// wailsSink forwards backend events to the Wails event bus and raises OS
+// notifications for "desktop" alert channels.
+type wailsSink struct {
+	app      *application.App
+	notifier *notifications.NotificationService
+}
+
+func (w *wailsSink) Emit(name string, data any) {
+	w.app.Event.Emit(name, data)
+}
+
+func (w *wailsSink) Notify(title, body string) error {
+	return w.notifier.SendNotification(notifications.NotificationOptions{
+		ID:    fmt.Sprintf("glancehud-alert-%d", time.Now().UnixNano()),
+		Title: title,
+		Body:  body,
+	})
+}
+
 func setupSystemTray(app *application.App, hudWindow application.Window, systemService *service.SystemService) {
 	tray := app.SystemTray.New()
 
@@ -190,6 +218,29 @@ func setupSystemTray(app *application.App, hudWindow application.Window, systemS
 
 	menu.AddSeparator()
 
+	// Snooze alert notifications (alerts are still evaluated and logged)
+	snoozeMenu := menu.AddSubmenu("Snooze Alerts")
+	snoozeDurations := []struct {
+		label string
+		value time.Duration
+	}{
+		{"15 minutes", 15 * time.Minute},
+		{"1 hour", time.Hour},
+		{"4 hours", 4 * time.Hour},
+		{"24 hours", 24 * time.Hour},
+	}
+	for _, d := range snoozeDurations {
+		snoozeMenu.Add(d.label).OnClick(func(ctx *application.Context) {
+			systemService.SnoozeNotifications(int(d.value.Seconds()))
+		})
+	}
+	snoozeMenu.AddSeparator()
+	snoozeMenu.Add("Resume").OnClick(func(ctx *application.Context) {
+		systemService.SnoozeNotifications(0)
+	})
+
+	menu.AddSeparator()
+
 	// Quit
 	menu.Add("Quit").OnClick(func(ctx *application.Context) {
 		app.Quit()
@@ -0,0 +1,38 @@
+//go:build headless
+
+package main
+
+import (
+	"glancehud/internal/service"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// Headless mode runs the monitoring core and HTTP API without a Wails window,
+// system tray or GUI toolkit. Build with `go build -tags headless`.
+//
+// Set GLANCEHUD_HOST=0.0.0.0 to make the API reachable from other machines.
+func main() {
+	systemService := service.NewSystemService()
+	systemService.SetHostOverrides(hostFlags())
+	apiService := service.NewAPIService(systemService)
+
+	// No window to notify: events are only traced at debug level.
+	systemService.Start(service.EventSinkFunc(func(name string, data any) {
+		slog.Debug("event", "name", name)
+	}))
+	apiService.Start()
+
+	slog.Info("GlanceHUD running headless", "version", Version)
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	<-sig
+
+	slog.Info("Shutting down")
+	if err := systemService.ServiceShutdown(); err != nil {
+		slog.Error("Shutdown failed", "error", err)
+	}
+}